@@ -0,0 +1,84 @@
+package access_log
+
+import (
+  "encoding/json"
+  "io"
+  "log"
+  "math/rand"
+  "net/http"
+  "os"
+  "sync"
+)
+
+// Entry is a line of the access log, one per suggest query.
+type Entry struct {
+  Time           string   `json:"time"`
+  Endpoint       string   `json:"endpoint"`
+  Index          string   `json:"index,omitempty"`
+  Prefix         string   `json:"prefix"`
+  Classes        []string `json:"classes,omitempty"`
+  ExcludeClasses []string `json:"exclude_classes,omitempty"`
+  Filters        []string `json:"filters,omitempty"`
+  Count          int      `json:"count"` // number of suggestions asked of the index, 0 for all
+  ResultsCount   int      `json:"results_count"`
+  LatencyMs      float64  `json:"latency_ms"`
+  Version        uint64   `json:"version"`
+  ClientId       string   `json:"client_id,omitempty"`
+  SampleRate     float64  `json:"sample_rate"`
+}
+
+// Logger writes the sampled queries as json lines. The sample rate is written along with every entry,
+// so that the counts can be scaled back.
+type Logger struct {
+  SampleRate float64
+
+  mutex  sync.Mutex
+  out    io.Writer
+  closer io.Closer
+}
+
+// NewLogger logs to the rotating file at the path or to stdout when the path is -.
+func NewLogger(path string, maxSize int64, maxFiles int, sampleRate float64) (*Logger, error) {
+  if path == "-" {
+    return &Logger{SampleRate: sampleRate, out: os.Stdout}, nil
+  }
+  file, err := OpenRotatingFile(path, maxSize, maxFiles)
+  if err != nil {
+    return nil, err
+  }
+  return &Logger{SampleRate: sampleRate, out: file, closer: file}, nil
+}
+
+// Sample tells whether the query should be logged, it is false for a nil logger.
+func (l *Logger) Sample() bool {
+  return l != nil && (l.SampleRate >= 1 || rand.Float64() < l.SampleRate)
+}
+
+func (l *Logger) Write(entry *Entry) {
+  entry.SampleRate = l.SampleRate
+  b, err := json.Marshal(entry)
+  if err != nil {
+    log.Printf("cannot marshal the access log entry: %v", err)
+    return
+  }
+  l.mutex.Lock()
+  defer l.mutex.Unlock()
+  if _, err := l.out.Write(append(b, '\n')); err != nil {
+    log.Printf("cannot write the access log: %v", err)
+  }
+}
+
+func (l *Logger) Close() error {
+  if l == nil || l.closer == nil {
+    return nil
+  }
+  return l.closer.Close()
+}
+
+// ClientId identifies the client application by the X-Client-Id header or the client-id parameter.
+func ClientId(r *http.Request) string {
+  if clientId := r.Header.Get("X-Client-Id"); clientId != "" {
+    return clientId
+  }
+  return r.URL.Query().Get("client-id")
+}
@@ -0,0 +1,86 @@
+package access_log
+
+import (
+  "fmt"
+  "os"
+  "sync"
+)
+
+// RotatingFile is a log file which is renamed to path.1 once it grows over MaxSize bytes, shifting the older
+// path.N files and keeping at most MaxFiles of them.
+type RotatingFile struct {
+  Path     string
+  MaxSize  int64
+  MaxFiles int
+
+  mutex sync.Mutex
+  file  *os.File
+  size  int64
+}
+
+func OpenRotatingFile(path string, maxSize int64, maxFiles int) (*RotatingFile, error) {
+  f := &RotatingFile{
+    Path:     path,
+    MaxSize:  maxSize,
+    MaxFiles: maxFiles,
+  }
+  if err := f.open(); err != nil {
+    return nil, err
+  }
+  return f, nil
+}
+
+func (f *RotatingFile) open() error {
+  file, err := os.OpenFile(f.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+  if err != nil {
+    return err
+  }
+  info, err := file.Stat()
+  if err != nil {
+    file.Close()
+    return err
+  }
+  f.file = file
+  f.size = info.Size()
+  return nil
+}
+
+func (f *RotatingFile) rotate() error {
+  if err := f.file.Close(); err != nil {
+    return err
+  }
+  if f.MaxFiles == 0 {
+    if err := os.Remove(f.Path); err != nil {
+      return err
+    }
+    return f.open()
+  }
+  for n := f.MaxFiles - 1; n > 0; n-- {
+    if err := os.Rename(fmt.Sprintf("%s.%d", f.Path, n), fmt.Sprintf("%s.%d", f.Path, n+1)); err != nil && !os.IsNotExist(err) {
+      return err
+    }
+  }
+  if err := os.Rename(f.Path, f.Path+".1"); err != nil {
+    return err
+  }
+  return f.open()
+}
+
+func (f *RotatingFile) Write(p []byte) (int, error) {
+  f.mutex.Lock()
+  defer f.mutex.Unlock()
+  if f.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.MaxSize {
+    if err := f.rotate(); err != nil {
+      return 0, fmt.Errorf("cannot rotate %s: %v", f.Path, err)
+    }
+  }
+  n, err := f.file.Write(p)
+  f.size += int64(n)
+  return n, err
+}
+
+func (f *RotatingFile) Close() error {
+  f.mutex.Lock()
+  defer f.mutex.Unlock()
+  return f.file.Close()
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+  "fmt"
+  "main/suggest"
+  "main/suggest_merger"
+  "runtime"
+)
+
+func NewBuildCommand() *Command {
+  c := NewCommand("build", "build suggest data, optionally sharded, from a tab-separated input file")
+  inputFilePath := c.Flags.String("input", "", "input data file path")
+  suggestDataPath := c.Flags.String("suggest", "", "suggest data file path, the manifest is written next to it")
+  outputDir := c.Flags.String("output-dir", "", "output directory for the suggest data files and their manifest")
+  maxItemsPerPrefix := c.Flags.Int("count", 10, "number of suggestions to return")
+  suffixSuggestFactor := c.Flags.Float64("suffix-factor", 1e-5, "a weight multiplier for the suffix suggest")
+  equalShapedNormalize := c.Flags.Bool("equal-shaped-normalize", false, "serve the suggest with the additional normalization for cyrillic symbols")
+  buildWithoutSuffixes := c.Flags.Bool("build-without-suffixes", false, "build suggest without suffixes")
+  countOutputFiles := c.Flags.Int("count-output-files", 0, "build suggest to N shard files")
+  sharding := c.Flags.String("sharding", suggest_merger.FirstCharsSharding, "sharding strategy for N shard files: first-chars, hash or field")
+  shardingPrefixLength := c.Flags.Int("sharding-prefix-length", 1, "number of first characters to shard by with the first-chars strategy")
+  shardingMaxPrefixLength := c.Flags.Int("sharding-max-prefix-length", 4, "max number of first characters to split hot prefixes by with the first-chars strategy")
+  shardingField := c.Flags.String("sharding-field", "", "data json field to shard by with the field strategy")
+  facets := &stringsFlag{}
+  c.Flags.Var(facets, "facet", "data json field to index for filtering, may be repeated")
+  storedOnlyFields := &stringsFlag{}
+  c.Flags.Var(storedOnlyFields, "stored-only-field", "data json field to keep in the index but leave out of the suggest responses, may be repeated")
+  buildParallelism := c.Flags.Int("build-parallelism", runtime.NumCPU(), "max number of shards built at the same time")
+
+  c.Run = func(_ []string) error {
+    if *inputFilePath == "" {
+      return fmt.Errorf("please specify the input data file via the --input parameter")
+    }
+    if *suggestDataPath == "" && *outputDir == "" {
+      return fmt.Errorf("please specify the build output via the --output-dir or --suggest parameter")
+    }
+    if *countOutputFiles < 0 {
+      return fmt.Errorf("the --count-output-files parameter must not be negative, got %d", *countOutputFiles)
+    }
+    if err := suggest.ValidateStoredOnlyFields(*storedOnlyFields); err != nil {
+      return err
+    }
+    layout := suggest.NewOutputLayout(*outputDir, *suggestDataPath)
+    parameters := &suggest.BuildParameters{
+      MaxItemsPerPrefix:    *maxItemsPerPrefix,
+      SuffixFactor:         *suffixSuggestFactor,
+      BuildWithoutSuffixes: *buildWithoutSuffixes,
+      Normalization:        suggest.DefaultNormalization,
+      Facets:               *facets,
+      StoredOnlyFields:     *storedOnlyFields,
+    }
+    if *equalShapedNormalize {
+      parameters.Normalization = suggest.EqualShapedNormalization
+    }
+    if *countOutputFiles == 0 {
+      suggest.DoBuildSuggest(*inputFilePath, layout, parameters)
+      return nil
+    }
+    suggest_merger.DoBuildShardedSuggest(*inputFilePath, layout, parameters, &suggest_merger.ShardingOptions{
+      Strategy:         *sharding,
+      PrefixLength:     *shardingPrefixLength,
+      MaxPrefixLength:  *shardingMaxPrefixLength,
+      Field:            *shardingField,
+      CountOutputFiles: *countOutputFiles,
+    }, *buildParallelism)
+    return nil
+  }
+  return c
+}
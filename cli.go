@@ -0,0 +1,117 @@
+package main
+
+import (
+  "encoding/json"
+  "flag"
+  "fmt"
+  "os"
+  "os/signal"
+  "path/filepath"
+  "sort"
+  "strconv"
+  "strings"
+  "syscall"
+)
+
+type Command struct {
+  Name        string
+  Description string
+  ArgsUsage   string
+  Flags       *flag.FlagSet
+  Run         func(args []string) error
+}
+
+func NewCommand(name, description string) *Command {
+  c := &Command{
+    Name:        name,
+    Description: description,
+    Flags:       flag.NewFlagSet(name, flag.ExitOnError),
+  }
+  c.Flags.String("config", "", "json file with values for the command flags, explicitly passed flags take precedence")
+  c.Flags.Usage = func() {
+    fmt.Fprintf(c.Flags.Output(), "usage: %s\n\n%s\n\nflags:\n", strings.TrimSpace(programName()+" "+c.Name+" [flags] "+c.ArgsUsage), c.Description)
+    c.Flags.PrintDefaults()
+  }
+  return c
+}
+
+// Parse parses the command line and then fills the flags which were not passed explicitly from the --config file.
+func (c *Command) Parse(arguments []string) error {
+  if err := c.Flags.Parse(arguments); err != nil {
+    return err
+  }
+  configPath := c.Flags.Lookup("config").Value.String()
+  if configPath == "" {
+    return nil
+  }
+  return applyConfigFile(c.Flags, configPath)
+}
+
+func applyConfigFile(flags *flag.FlagSet, configPath string) error {
+  b, err := os.ReadFile(configPath)
+  if err != nil {
+    return err
+  }
+  values := map[string]interface{}{}
+  if err := json.Unmarshal(b, &values); err != nil {
+    return fmt.Errorf("cannot parse config %s: %v", configPath, err)
+  }
+
+  passedFlags := map[string]bool{}
+  flags.Visit(func(f *flag.Flag) {
+    passedFlags[f.Name] = true
+  })
+
+  names := make([]string, 0, len(values))
+  for name := range values {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+  for _, name := range names {
+    if name == "config" || flags.Lookup(name) == nil {
+      return fmt.Errorf("config %s: unknown flag %q", configPath, name)
+    }
+    if passedFlags[name] {
+      continue
+    }
+    flagValues, ok := values[name].([]interface{})
+    if !ok {
+      flagValues = []interface{}{values[name]}
+    }
+    for _, value := range flagValues {
+      if err := flags.Set(name, configValueString(value)); err != nil {
+        return fmt.Errorf("config %s: invalid value for %q: %v", configPath, name, err)
+      }
+    }
+  }
+  return nil
+}
+
+func configValueString(value interface{}) string {
+  if number, ok := value.(float64); ok {
+    return strconv.FormatFloat(number, 'f', -1, 64)
+  }
+  return fmt.Sprint(value)
+}
+
+// stringsFlag collects the values of a repeated flag.
+type stringsFlag []string
+
+func (sf *stringsFlag) String() string {
+  return strings.Join(*sf, ",")
+}
+
+func (sf *stringsFlag) Set(value string) error {
+  *sf = append(*sf, value)
+  return nil
+}
+
+func programName() string {
+  return filepath.Base(os.Args[0])
+}
+
+func waitForExitSignal() os.Signal {
+  exitSignal := make(chan os.Signal, 1)
+  signal.Notify(exitSignal, syscall.SIGINT, syscall.SIGTERM)
+  return <-exitSignal
+}
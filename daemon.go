@@ -0,0 +1,205 @@
+package main
+
+import (
+  "context"
+  "google.golang.org/grpc"
+  "log"
+  "main/network"
+  "net"
+  "net/http"
+  "os"
+  "sync/atomic"
+  "syscall"
+  "time"
+)
+
+// daemonFlags are the flags of the commands serving over http.
+type daemonFlags struct {
+  readTimeout     *time.Duration
+  writeTimeout    *time.Duration
+  idleTimeout     *time.Duration
+  shutdownDelay   *time.Duration
+  shutdownTimeout *time.Duration
+}
+
+func addDaemonFlags(c *Command) *daemonFlags {
+  return &daemonFlags{
+    readTimeout:     c.Flags.Duration("read-timeout", 10*time.Second, "max time to read a request, the body included"),
+    writeTimeout:    c.Flags.Duration("write-timeout", 30*time.Second, "max time from the end of the request headers to the end of the response"),
+    idleTimeout:     c.Flags.Duration("idle-timeout", 2*time.Minute, "max time to keep an idle keep-alive connection"),
+    shutdownDelay:   c.Flags.Duration("shutdown-delay", 5*time.Second, "time to report not ready on SIGTERM before draining, so that the load balancers stop sending requests"),
+    shutdownTimeout: c.Flags.Duration("shutdown-timeout", 30*time.Second, "max time to drain the in-flight requests on shutdown"),
+  }
+}
+
+func (df *daemonFlags) newDaemon(port string) *Daemon {
+  return &Daemon{
+    HttpServer: &http.Server{
+      Addr:         ":" + port,
+      ReadTimeout:  *df.readTimeout,
+      WriteTimeout: *df.writeTimeout,
+      IdleTimeout:  *df.idleTimeout,
+      ConnContext:  withConn,
+    },
+    ShutdownDelay:   *df.shutdownDelay,
+    ShutdownTimeout: *df.shutdownTimeout,
+  }
+}
+
+// newAdminServer makes the server of the private endpoints, e.g. changing the daemon state or exposing the stored
+// data, listening on its own port so that they are not exposed along with the public ones.
+func (df *daemonFlags) newAdminServer(port string) *http.Server {
+  return &http.Server{
+    Addr:         ":" + port,
+    ReadTimeout:  *df.readTimeout,
+    WriteTimeout: *df.writeTimeout,
+    IdleTimeout:  *df.idleTimeout,
+    ConnContext:  withConn,
+  }
+}
+
+type connContextKey struct{}
+
+func withConn(ctx context.Context, conn net.Conn) context.Context {
+  return context.WithValue(ctx, connContextKey{}, conn)
+}
+
+// withoutWriteTimeout lifts the write timeout for the responses of the handler, e.g. of an export streaming
+// the whole index. The server sets the deadline again before reading the next request of the connection.
+func withoutWriteTimeout(handler http.HandlerFunc) http.HandlerFunc {
+  return func(w http.ResponseWriter, r *http.Request) {
+    if conn, ok := r.Context().Value(connContextKey{}).(net.Conn); ok {
+      if err := conn.SetWriteDeadline(time.Time{}); err != nil {
+        log.Printf("cannot lift the write timeout: %v", err)
+      }
+    }
+    handler(w, r)
+  }
+}
+
+// healthChecker is the served component, e.g. the suggest handler or the merger, which tells whether it can take
+// requests and describes its state.
+type healthChecker interface {
+  Health() (bool, interface{})
+}
+
+// Daemon runs the http server on the default mux and, optionally, the admin and the grpc ones, reports its liveness and readiness
+// and drains the requests on shutdown.
+type Daemon struct {
+  HttpServer      *http.Server
+  AdminServer     *http.Server
+  GrpcServer      *grpc.Server
+  GrpcPort        string
+  ShutdownDelay   time.Duration
+  ShutdownTimeout time.Duration
+  Component       healthChecker
+
+  serving   int32
+  startedAt time.Time
+}
+
+type liveResponse struct {
+  Live          bool    `json:"live"`
+  UptimeSeconds float64 `json:"uptime_seconds"`
+}
+
+type readyResponse struct {
+  Ready        bool        `json:"ready"`
+  ShuttingDown bool        `json:"shutting_down"`
+  Details      interface{} `json:"details,omitempty"`
+}
+
+// Ready tells whether the daemon is not shutting down and its component can take requests.
+func (d *Daemon) Ready() (bool, *readyResponse) {
+  response := &readyResponse{ShuttingDown: atomic.LoadInt32(&d.serving) == 0}
+  componentReady := true
+  if d.Component != nil {
+    componentReady, response.Details = d.Component.Health()
+  }
+  response.Ready = !response.ShuttingDown && componentReady
+  return response.Ready, response
+}
+
+func (d *Daemon) HandleLiveRequest(w http.ResponseWriter, _ *http.Request) {
+  network.ReportSuccessData(w, &liveResponse{
+    Live:          true,
+    UptimeSeconds: time.Since(d.startedAt).Seconds(),
+  })
+}
+
+func (d *Daemon) HandleReadyRequest(w http.ResponseWriter, _ *http.Request) {
+  ready, response := d.Ready()
+  if !ready {
+    network.ReportData(w, http.StatusServiceUnavailable, response)
+    return
+  }
+  network.ReportSuccessData(w, response)
+}
+
+// serveHttp binds the port right away, so that the daemon is reported ready only when it accepts connections.
+func serveHttp(server *http.Server, name string) {
+  listener, err := net.Listen("tcp", server.Addr)
+  if err != nil {
+    log.Fatalf("cannot listen on the %s port: %v", name, err)
+  }
+  go func() {
+    if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+      log.Fatalf("fatal error in %s Serve: %v", name, err)
+    }
+  }()
+}
+
+func (d *Daemon) Serve() {
+  serveHttp(d.HttpServer, "http")
+  if d.AdminServer != nil {
+    serveHttp(d.AdminServer, "admin")
+  }
+  if d.GrpcServer != nil {
+    listener, err := net.Listen("tcp", ":"+d.GrpcPort)
+    if err != nil {
+      log.Fatalf("cannot listen on the grpc port: %v", err)
+    }
+    go func() {
+      if err := d.GrpcServer.Serve(listener); err != nil {
+        log.Fatalf("fatal error in grpc Serve: %v", err)
+      }
+    }()
+  }
+  d.startedAt = time.Now()
+  atomic.StoreInt32(&d.serving, 1)
+}
+
+// Shutdown reports not ready, waits for the shutdown delay on SIGTERM and then stops accepting connections
+// and waits for the in-flight requests up to the shutdown timeout.
+func (d *Daemon) Shutdown(exitSignal os.Signal) {
+  atomic.StoreInt32(&d.serving, 0)
+  if exitSignal == syscall.SIGTERM && d.ShutdownDelay > 0 {
+    log.Printf("not ready, shutting down in %v", d.ShutdownDelay)
+    time.Sleep(d.ShutdownDelay)
+  }
+  log.Println("draining the requests")
+  ctx, cancel := context.WithTimeout(context.Background(), d.ShutdownTimeout)
+  defer cancel()
+  if d.GrpcServer != nil {
+    stopped := make(chan struct{})
+    go func() {
+      d.GrpcServer.GracefulStop()
+      close(stopped)
+    }()
+    defer func() {
+      select {
+      case <-stopped:
+      case <-ctx.Done():
+        d.GrpcServer.Stop()
+      }
+    }()
+  }
+  if err := d.HttpServer.Shutdown(ctx); err != nil {
+    log.Printf("cannot drain the requests: %v", err)
+  }
+  if d.AdminServer != nil {
+    if err := d.AdminServer.Shutdown(ctx); err != nil {
+      log.Printf("cannot drain the admin requests: %v", err)
+    }
+  }
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+  "encoding/json"
+  "fmt"
+  stpb "main/proto/suggest/suggest_trie"
+  "main/suggest"
+  "os"
+  "strings"
+  "text/tabwriter"
+)
+
+// loadSuggestPath loads either a suggest data file or the single file of a build manifest.
+func loadSuggestPath(path string) (*stpb.SuggestData, int64, error) {
+  info, err := os.Stat(path)
+  if err != nil {
+    return nil, 0, err
+  }
+  if !info.IsDir() && !strings.HasSuffix(path, ".json") {
+    suggestData, err := suggest.LoadSuggest(path)
+    return suggestData, info.Size(), err
+  }
+  manifest, err := suggest.ReadManifest(path)
+  if err != nil {
+    return nil, 0, err
+  }
+  if len(manifest.Files) != 1 {
+    return nil, 0, fmt.Errorf("%s describes a sharded build, diff its shard files one by one", path)
+  }
+  suggestData, err := manifest.LoadFile(manifest.Files[0])
+  return suggestData, int64(manifest.Files[0].Size), err
+}
+
+func NewDiffCommand() *Command {
+  c := NewCommand("diff", "compare two index files: items added, removed and reweighted, changed top items of prefixes, classes and sizes")
+  c.ArgsUsage = "<old> <new>"
+  maxPrefixLength := c.Flags.Int("max-prefix-length", 3, "compare the top items of prefixes up to this length")
+  topCount := c.Flags.Int("top", 10, "number of top items to compare for every prefix")
+  maxListed := c.Flags.Int("max-listed", 20, "max number of items and prefixes listed in every section of the text report, 0 for all")
+  asJson := c.Flags.Bool("json", false, "print the full report as json")
+
+  c.Run = func(args []string) error {
+    if len(args) != 2 {
+      return fmt.Errorf("please specify the old and the new index paths")
+    }
+    oldData, oldSize, err := loadSuggestPath(args[0])
+    if err != nil {
+      return fmt.Errorf("cannot load %s: %v", args[0], err)
+    }
+    newData, newSize, err := loadSuggestPath(args[1])
+    if err != nil {
+      return fmt.Errorf("cannot load %s: %v", args[1], err)
+    }
+
+    report := suggest.Diff(oldData, newData, &suggest.DiffOptions{
+      MaxPrefixLength: *maxPrefixLength,
+      TopCount:        *topCount,
+    })
+
+    if *asJson {
+      b, err := json.MarshalIndent(struct {
+        OldSize int64 `json:"old_size"`
+        NewSize int64 `json:"new_size"`
+        *suggest.DiffReport
+      }{oldSize, newSize, report}, "", "  ")
+      if err != nil {
+        return err
+      }
+      _, err = fmt.Fprintln(os.Stdout, string(b))
+      return err
+    }
+
+    w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+    limit := func(n int) int {
+      if *maxListed > 0 && n > *maxListed {
+        return *maxListed
+      }
+      return n
+    }
+
+    fmt.Fprintf(w, "\told\tnew\n")
+    fmt.Fprintf(w, "version\t%d\t%d\n", report.OldStats.Version, report.NewStats.Version)
+    fmt.Fprintf(w, "size\t%d\t%d\t%+d\n", oldSize, newSize, newSize-oldSize)
+    fmt.Fprintf(w, "items\t%d\t%d\t%+d\n", report.OldStats.ItemsCount, report.NewStats.ItemsCount, report.NewStats.ItemsCount-report.OldStats.ItemsCount)
+    fmt.Fprintf(w, "nodes\t%d\t%d\t%+d\n", report.OldStats.NodesCount, report.NewStats.NodesCount, report.NewStats.NodesCount-report.OldStats.NodesCount)
+
+    fmt.Fprintf(w, "\nclasses changed: %d\n", len(report.Classes))
+    for _, change := range report.Classes {
+      fmt.Fprintf(w, "%q\t%d\t%d\t%+d\n", change.Class, change.Before, change.After, change.After-change.Before)
+    }
+
+    printItems := func(title string, changes []*suggest.ItemChange) {
+      fmt.Fprintf(w, "\n%s: %d\n", title, len(changes))
+      for _, change := range changes[:limit(len(changes))] {
+        fmt.Fprintf(w, "%s\t%g\t%g\t%s\n", change.Id, change.OldWeight, change.NewWeight, change.Text)
+      }
+    }
+    printItems("items added", report.Added)
+    printItems("items removed", report.Removed)
+    printItems("items reweighted", report.Reweighted)
+
+    fmt.Fprintf(w, "\nprefixes with changed top %d: %d\n", *topCount, len(report.ChangedPrefixes))
+    for _, change := range report.ChangedPrefixes[:limit(len(report.ChangedPrefixes))] {
+      fmt.Fprintf(w, "%q\t%s\t->\t%s\n", change.Prefix, strings.Join(change.Before, ", "), strings.Join(change.After, ", "))
+    }
+    return w.Flush()
+  }
+  return c
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+  "log"
+  "main/metrics"
+  "main/suggest_registry"
+  "net/http"
+)
+
+func NewIndexesCommand() *Command {
+  c := NewCommand("indexes", "serve several named indexes from one daemon, routed by /suggest/{index} or the index parameter")
+  configPath := c.Flags.String("indexes-config", "", "json config with the named indexes and their settings")
+  dir := c.Flags.String("indexes-dir", "", "directory of indexes: build output directories and *.data files, named after them")
+  maxBatchSize := c.Flags.Int("max-batch-size", 100, "max number of queries in a batch request, 0 for unlimited")
+  port := c.Flags.String("port", "8080", "daemon port")
+  adminPort := c.Flags.String("admin-port", "8081", "port of the admin endpoints loading and unloading the indexes, keep it private")
+  accessLog := addAccessLogFlags(c)
+  daemonFlags := addDaemonFlags(c)
+
+  c.Run = func(_ []string) error {
+    registry, err := suggest_registry.NewRegistry(*configPath, *dir)
+    if err != nil {
+      return err
+    }
+    registry.MaxBatchSize = *maxBatchSize
+    if registry.AccessLog, err = accessLog.newLogger(); err != nil {
+      return err
+    }
+    defer registry.AccessLog.Close()
+    if err := registry.LoadAll(); err != nil {
+      return err
+    }
+    daemon := daemonFlags.newDaemon(*port)
+    daemon.AdminServer = daemonFlags.newAdminServer(*adminPort)
+    RunServingIndexes(registry, daemon)
+    daemon.Shutdown(waitForExitSignal())
+    return nil
+  }
+  return c
+}
+
+func RunServingIndexes(registry *suggest_registry.Registry, daemon *Daemon) {
+  log.Printf("ready to serve %d indexes", len(registry.Names()))
+
+  http.Handle("/suggest", http.HandlerFunc(registry.HandleSuggestRequest))
+  http.Handle("/suggest/", http.HandlerFunc(registry.HandleSuggestRequest))
+  http.Handle("/indexes", http.HandlerFunc(registry.HandleIndexesRequest))
+  http.Handle("/metrics", http.HandlerFunc(metrics.Default.HandleMetricsRequest))
+  http.Handle("/health", http.HandlerFunc(registry.HandleHealthRequest))
+  http.Handle("/health/live", http.HandlerFunc(daemon.HandleLiveRequest))
+  http.Handle("/health/ready", http.HandlerFunc(daemon.HandleReadyRequest))
+
+  if daemon.AdminServer != nil {
+    admin := http.NewServeMux()
+    admin.Handle("/indexes/load", http.HandlerFunc(registry.HandleLoadRequest))
+    admin.Handle("/indexes/unload", http.HandlerFunc(registry.HandleUnloadRequest))
+    daemon.AdminServer.Handler = admin
+  }
+
+  daemon.Component = registry
+  daemon.Serve()
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+  "fmt"
+  "main/suggest"
+  "os"
+  "sort"
+  "text/tabwriter"
+)
+
+func NewInspectCommand() *Command {
+  c := NewCommand("inspect", "print statistics of an index file and, with --prefix, the top items stored for the prefix")
+  source := addSuggestSourceFlags(c)
+  equalShapedNormalize := c.Flags.Bool("equal-shaped-normalize", false, "additional normalization for cyrillic symbols")
+  prefix := c.Flags.String("prefix", "", "prefix to print the top items for")
+  top := c.Flags.Int("top", 10, "number of top items to print for the prefix")
+
+  c.Run = func(_ []string) error {
+    if *top < 0 {
+      return fmt.Errorf("the --top parameter must not be negative, got %d", *top)
+    }
+    h, err := source.newHandler(*equalShapedNormalize)
+    if err != nil {
+      return err
+    }
+    w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+
+    stats := suggest.CollectStats(h.Suggest)
+    fmt.Fprintf(w, "version\t%d\n", stats.Version)
+    fmt.Fprintf(w, "items\t%d\n", stats.ItemsCount)
+    fmt.Fprintf(w, "nodes\t%d\n", stats.NodesCount)
+    fmt.Fprintf(w, "max depth\t%d\n", stats.MaxDepth)
+    fmt.Fprintf(w, "weights\t%g .. %g\n", stats.MinWeight, stats.MaxWeight)
+
+    classes := make([]string, 0, len(stats.ClassesCount))
+    for class := range stats.ClassesCount {
+      classes = append(classes, class)
+    }
+    sort.Slice(classes, func(i, j int) bool {
+      return stats.ClassesCount[classes[i]] > stats.ClassesCount[classes[j]]
+    })
+    fmt.Fprintf(w, "\nclass\titems\n")
+    for _, class := range classes {
+      fmt.Fprintf(w, "%q\t%d\n", class, stats.ClassesCount[class])
+    }
+
+    if *prefix != "" {
+      _, normalizedPrefix := h.NormalizePart(*prefix)
+      items := suggest.GetTopItems(h.Suggest, normalizedPrefix)
+      if len(items) > *top {
+        items = items[:*top]
+      }
+      fmt.Fprintf(w, "\nitems matching %q\t%d\n", normalizedPrefix, h.PrefixItemsCount(*prefix))
+      fmt.Fprintf(w, "\ntop items for %q\nweight\tmatch\tclass\ttext\n", normalizedPrefix)
+      for _, item := range items {
+        fmt.Fprintf(w, "%g\t%s\t%s\t%s\n", item.Weight, suggest.MatchTypeName(item.MatchType), suggest.ItemClass(item.Item), item.Item.OriginalText)
+      }
+    }
+    return w.Flush()
+  }
+  return c
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+  "flag"
+  "fmt"
+  "log"
+  "strings"
+)
+
+// legacyBoolFlags are the boolean flags of the command line used before the subcommands.
+var legacyBoolFlags = []string{"merger-on", "merger-local", "equal-shaped-normalize", "build-without-suffixes"}
+
+// legacyValueFlags are the other flags of the command line used before the subcommands.
+var legacyValueFlags = []string{
+  "input", "suggest", "output-dir", "manifest", "count", "suffix-factor", "count-output-files", "sharding",
+  "sharding-prefix-length", "sharding-max-prefix-length", "sharding-field", "build-parallelism",
+  "merger-config", "merger-reload-interval", "port", "grpc-port", "verify",
+}
+
+// legacyRenamedFlags maps the flags which are named differently by the subcommands.
+var legacyRenamedFlags = map[string]string{
+  "merger-local":           "local",
+  "merger-reload-interval": "reload-interval",
+}
+
+// isLegacyCommandLine tells whether the arguments use the deprecated flags instead of a subcommand.
+func isLegacyCommandLine(args []string) bool {
+  return len(args) > 0 && strings.HasPrefix(args[0], "-") && args[0] != "-h" && args[0] != "--help"
+}
+
+// legacyCommandLine translates the deprecated command line into the subcommand doing the same and its arguments:
+// --verify means verify, --input means build, --merger-on means merge and everything else means serve.
+// The flags the subcommand does not have were ignored by the old command line in its mode and are dropped.
+func legacyCommandLine(args []string, commands []*Command) (*Command, []string, error) {
+  flags := flag.NewFlagSet(programName(), flag.ContinueOnError)
+  flags.Usage = func() {
+    usage(commands)
+  }
+  for _, name := range legacyBoolFlags {
+    flags.Bool(name, false, "")
+  }
+  for _, name := range legacyValueFlags {
+    flags.String(name, "", "")
+  }
+  if err := flags.Parse(args); err != nil {
+    return nil, nil, err
+  }
+  passed := map[string]string{}
+  flags.Visit(func(f *flag.Flag) {
+    passed[f.Name] = f.Value.String()
+  })
+
+  name := "serve"
+  switch {
+  case passed["verify"] != "":
+    name = "verify"
+  case passed["input"] != "":
+    name = "build"
+  case passed["merger-on"] == "true":
+    name = "merge"
+  }
+  var command *Command
+  for _, c := range commands {
+    if c.Name == name {
+      command = c
+    }
+  }
+
+  var commandArgs []string
+  flags.Visit(func(f *flag.Flag) {
+    newName := f.Name
+    if renamed, ok := legacyRenamedFlags[f.Name]; ok {
+      newName = renamed
+    }
+    if command.Flags.Lookup(newName) == nil {
+      return
+    }
+    commandArgs = append(commandArgs, "--"+newName+"="+f.Value.String())
+  })
+  if name == "verify" {
+    commandArgs = []string{passed["verify"]}
+  }
+  commandArgs = append(commandArgs, flags.Args()...)
+  log.Printf("the command line without a subcommand is deprecated, run: %s %s %s", programName(), name, strings.Join(commandArgs, " "))
+  return command, commandArgs, nil
+}
+
+// runLegacyCommandLine runs the subcommand the deprecated command line maps to.
+func runLegacyCommandLine(args []string, commands []*Command) error {
+  c, commandArgs, err := legacyCommandLine(args, commands)
+  if err != nil {
+    return fmt.Errorf("cannot parse the deprecated command line: %v", err)
+  }
+  if err := c.Parse(commandArgs); err != nil {
+    return err
+  }
+  return c.Run(c.Flags.Args())
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+  "fmt"
+  "log"
+  "main/access_log"
+  "main/metrics"
+  "main/suggest"
+  "main/suggest_merger"
+  "net/http"
+  "time"
+)
+
+func NewMergeCommand() *Command {
+  c := NewCommand("merge", "serve suggest merged from shards, either remote ones or loaded in-process with --local")
+  mergerConfigPath := c.Flags.String("merger-config", "", "merger config with the remote shards")
+  reloadInterval := c.Flags.Duration("reload-interval", 10*time.Second, "how often to check the merger config for changes, 0 to reload on SIGHUP only")
+  local := c.Flags.Bool("local", false, "load the shards in-process from the --manifest or --suggest path instead of calling remote ones")
+  suggestDataPath := c.Flags.String("suggest", "", "suggest data file path the shards were built for, suggest_N.* files are loaded")
+  manifestPath := c.Flags.String("manifest", "", "sharded build manifest or build output directory, the remote shards serving it are resolved with --shard-url-template")
+  shardUrlTemplate := c.Flags.String("shard-url-template", "", "url of the remote shards serving the --manifest files, with "+suggest_merger.ShardNumberPlaceholder+" replaced by the shard number")
+  equalShapedNormalize := c.Flags.Bool("equal-shaped-normalize", false, "additional normalization for cyrillic symbols of the local shards")
+  healthCheckInterval := c.Flags.Duration("health-check-interval", 5*time.Second, "how often to check the readiness of the remote shards")
+  minHealthyShards := c.Flags.Float64("min-healthy-shards", suggest_merger.DefaultMinHealthyShards, "share of the healthy remote shards the merger needs to report ready")
+  port := c.Flags.String("port", "8080", "daemon port")
+  accessLogFlags := addAccessLogFlags(c)
+  daemonFlags := addDaemonFlags(c)
+
+  c.Run = func(_ []string) error {
+    accessLog, err := accessLogFlags.newLogger()
+    if err != nil {
+      return err
+    }
+    defer accessLog.Close()
+    daemon := daemonFlags.newDaemon(*port)
+    if !*local {
+      if *healthCheckInterval <= 0 {
+        return fmt.Errorf("the health check interval should be positive")
+      }
+      mergerConfig, err := readMergerConfig(*mergerConfigPath, *manifestPath, *shardUrlTemplate)
+      if err != nil {
+        return err
+      }
+      if err := RunServingSuggestMerger(mergerConfig, *mergerConfigPath, daemon, *reloadInterval, *healthCheckInterval, *minHealthyShards, accessLog); err != nil {
+        return err
+      }
+      daemon.Shutdown(waitForExitSignal())
+      return nil
+    }
+
+    var manifest *suggest.Manifest
+    if *manifestPath != "" {
+      if manifest, err = suggest.ReadManifest(*manifestPath); err != nil {
+        return err
+      }
+    } else if *suggestDataPath == "" {
+      return fmt.Errorf("please specify the shards via the --manifest or --suggest parameter")
+    }
+    if err := RunServingLocalSuggestMerger(*suggestDataPath, manifest, daemon, *equalShapedNormalize, accessLog); err != nil {
+      return err
+    }
+    daemon.Shutdown(waitForExitSignal())
+    return nil
+  }
+  return c
+}
+
+// readMergerConfig reads the remote shards either from the merger config or from the manifest of the build they serve.
+func readMergerConfig(mergerConfigPath, manifestPath, shardUrlTemplate string) (*suggest_merger.Config, error) {
+  if manifestPath == "" {
+    if mergerConfigPath == "" {
+      return nil, fmt.Errorf("please specify the merger config data path via the --merger-config parameter or the shards via the --manifest one")
+    }
+    mergerConfig, err := suggest_merger.ReadConfig(mergerConfigPath)
+    if err != nil {
+      return nil, fmt.Errorf("cannot read merger-config: %v", err)
+    }
+    return mergerConfig, nil
+  }
+  if mergerConfigPath != "" {
+    return nil, fmt.Errorf("please specify the remote shards either via the --merger-config or the --manifest parameter")
+  }
+  if shardUrlTemplate == "" {
+    return nil, fmt.Errorf("please specify the url of the remote shards serving the manifest via the --shard-url-template parameter")
+  }
+  manifest, err := suggest.ReadManifest(manifestPath)
+  if err != nil {
+    return nil, err
+  }
+  return suggest_merger.NewManifestConfig(manifest, shardUrlTemplate)
+}
+
+// RunServingSuggestMerger serves the merger of the configured remote shards, the config is watched for changes
+// when it was read from mergerConfigPath.
+func RunServingSuggestMerger(
+  mergerConfig *suggest_merger.Config,
+  mergerConfigPath string,
+  daemon *Daemon,
+  reloadInterval time.Duration,
+  healthCheckInterval time.Duration,
+  minHealthyShards float64,
+  accessLog *access_log.Logger,
+) error {
+  mh, err := suggest_merger.NewHandler(mergerConfig)
+  if err != nil {
+    return fmt.Errorf("invalid merger-config: %v", err)
+  }
+  mh.AccessLog = accessLog
+  mh.MinHealthyShards = minHealthyShards
+
+  if mergerConfigPath != "" {
+    go suggest_merger.NewConfigWatcher(mergerConfigPath, mh, reloadInterval).Run()
+  }
+  go mh.RunHealthChecks(healthCheckInterval)
+
+  log.Println("merger ready to serve")
+
+  http.Handle("/suggest", http.HandlerFunc(mh.HandleMergerSuggestRequest))
+  http.Handle("/metrics", http.HandlerFunc(metrics.Default.HandleMetricsRequest))
+  http.Handle("/health", http.HandlerFunc(mh.HandleMergerHealthRequest))
+  http.Handle("/health/live", http.HandlerFunc(daemon.HandleLiveRequest))
+  http.Handle("/health/ready", http.HandlerFunc(daemon.HandleReadyRequest))
+
+  daemon.Component = mh
+  daemon.Serve()
+  return nil
+}
+
+func RunServingLocalSuggestMerger(suggestDataPath string, manifest *suggest.Manifest, daemon *Daemon, equalShapedNormalize bool, accessLog *access_log.Logger) error {
+  var shards []suggest_merger.Shard
+  var err error
+  if manifest != nil {
+    equalShapedNormalize = equalShapedNormalize || manifest.Parameters.EqualShapedNormalize()
+    shards, err = suggest_merger.LoadManifestShards(manifest, equalShapedNormalize)
+  } else {
+    shards, err = suggest_merger.LoadLocalShards(suggestDataPath, equalShapedNormalize)
+  }
+  if err != nil {
+    return err
+  }
+
+  mh := suggest_merger.NewLocalHandler(shards)
+  mh.AccessLog = accessLog
+
+  log.Printf("merger ready to serve %d local shards", len(shards))
+
+  http.Handle("/suggest", http.HandlerFunc(mh.HandleMergerSuggestRequest))
+  http.Handle("/metrics", http.HandlerFunc(metrics.Default.HandleMetricsRequest))
+  http.Handle("/health", http.HandlerFunc(mh.HandleMergerHealthRequest))
+  http.Handle("/health/live", http.HandlerFunc(daemon.HandleLiveRequest))
+  http.Handle("/health/ready", http.HandlerFunc(daemon.HandleReadyRequest))
+
+  daemon.Component = mh
+  daemon.Serve()
+  return nil
+}
@@ -0,0 +1,156 @@
+package metrics
+
+import (
+  "math"
+  "sort"
+  "sync"
+  "sync/atomic"
+)
+
+// DefaultLatencyBuckets are the histogram bounds in seconds for the request latencies, from 100µs to 10s.
+var DefaultLatencyBuckets = []float64{0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type Counter struct {
+  value uint64
+}
+
+func (c *Counter) Inc() {
+  atomic.AddUint64(&c.value, 1)
+}
+
+func (c *Counter) Add(delta uint64) {
+  atomic.AddUint64(&c.value, delta)
+}
+
+func (c *Counter) Value() uint64 {
+  return atomic.LoadUint64(&c.value)
+}
+
+type Gauge struct {
+  bits uint64
+}
+
+func (g *Gauge) Set(value float64) {
+  atomic.StoreUint64(&g.bits, math.Float64bits(value))
+}
+
+func (g *Gauge) Value() float64 {
+  return math.Float64frombits(atomic.LoadUint64(&g.bits))
+}
+
+// Histogram counts the observations per bucket, the buckets are cumulated only when written out.
+type Histogram struct {
+  sumBits uint64
+  bounds  []float64
+  counts  []uint64
+}
+
+func newHistogram(bounds []float64) *Histogram {
+  return &Histogram{
+    bounds: bounds,
+    counts: make([]uint64, len(bounds)+1),
+  }
+}
+
+func (h *Histogram) Observe(value float64) {
+  atomic.AddUint64(&h.counts[sort.SearchFloat64s(h.bounds, value)], 1)
+  for {
+    oldBits := atomic.LoadUint64(&h.sumBits)
+    newBits := math.Float64bits(math.Float64frombits(oldBits) + value)
+    if atomic.CompareAndSwapUint64(&h.sumBits, oldBits, newBits) {
+      return
+    }
+  }
+}
+
+// vec keeps the metrics of a family by their label values. The lookups of the existing metrics do not lock.
+type vec struct {
+  name       string
+  help       string
+  labelNames []string
+  newMetric  func() interface{}
+
+  mutex   sync.Mutex
+  metrics sync.Map
+}
+
+type labeledMetric struct {
+  labelValues []string
+  metric      interface{}
+}
+
+func labelsKey(labelValues []string) string {
+  key := ""
+  for _, value := range labelValues {
+    key += value + "\xff"
+  }
+  return key
+}
+
+func (v *vec) with(labelValues []string) interface{} {
+  if len(labelValues) != len(v.labelNames) {
+    panic("metrics: " + v.name + " expects the values of the labels " + labelsKey(v.labelNames))
+  }
+  key := labelsKey(labelValues)
+  if m, ok := v.metrics.Load(key); ok {
+    return m.(*labeledMetric).metric
+  }
+  v.mutex.Lock()
+  defer v.mutex.Unlock()
+  m, _ := v.metrics.LoadOrStore(key, &labeledMetric{
+    labelValues: append([]string{}, labelValues...),
+    metric:      v.newMetric(),
+  })
+  return m.(*labeledMetric).metric
+}
+
+func (v *vec) delete(labelValues []string) {
+  v.metrics.Delete(labelsKey(labelValues))
+}
+
+// sorted returns the metrics of the family ordered by their label values, so that the output is stable.
+func (v *vec) sorted() []*labeledMetric {
+  var metrics []*labeledMetric
+  v.metrics.Range(func(_, m interface{}) bool {
+    metrics = append(metrics, m.(*labeledMetric))
+    return true
+  })
+  sort.Slice(metrics, func(i, j int) bool {
+    for k := range metrics[i].labelValues {
+      if metrics[i].labelValues[k] != metrics[j].labelValues[k] {
+        return metrics[i].labelValues[k] < metrics[j].labelValues[k]
+      }
+    }
+    return false
+  })
+  return metrics
+}
+
+type CounterVec struct {
+  *vec
+}
+
+func (cv *CounterVec) With(labelValues ...string) *Counter {
+  return cv.with(labelValues).(*Counter)
+}
+
+type GaugeVec struct {
+  *vec
+}
+
+func (gv *GaugeVec) With(labelValues ...string) *Gauge {
+  return gv.with(labelValues).(*Gauge)
+}
+
+// Delete drops the gauge, e.g. of an index which is not served anymore.
+func (gv *GaugeVec) Delete(labelValues ...string) {
+  gv.delete(labelValues)
+}
+
+type HistogramVec struct {
+  *vec
+}
+
+func (hv *HistogramVec) With(labelValues ...string) *Histogram {
+  return hv.with(labelValues).(*Histogram)
+}
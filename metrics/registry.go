@@ -0,0 +1,130 @@
+package metrics
+
+import (
+  "bufio"
+  "fmt"
+  "log"
+  "math"
+  "net/http"
+  "strconv"
+  "strings"
+  "sync"
+  "sync/atomic"
+)
+
+// Registry keeps the metric families and writes them out in the Prometheus text format.
+type Registry struct {
+  mutex    sync.Mutex
+  families []*family
+}
+
+type family struct {
+  kind string
+  vec  *vec
+}
+
+// Default is the registry the daemons expose on /metrics.
+var Default = &Registry{}
+
+func (r *Registry) register(kind, name, help string, labelNames []string, newMetric func() interface{}) *vec {
+  v := &vec{
+    name:       name,
+    help:       help,
+    labelNames: labelNames,
+    newMetric:  newMetric,
+  }
+  r.mutex.Lock()
+  defer r.mutex.Unlock()
+  for _, f := range r.families {
+    if f.vec.name == name {
+      panic("metrics: " + name + " is registered twice")
+    }
+  }
+  r.families = append(r.families, &family{kind: kind, vec: v})
+  return v
+}
+
+func (r *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+  return &CounterVec{r.register("counter", name, help, labelNames, func() interface{} {
+    return &Counter{}
+  })}
+}
+
+func (r *Registry) NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+  return &GaugeVec{r.register("gauge", name, help, labelNames, func() interface{} {
+    return &Gauge{}
+  })}
+}
+
+func (r *Registry) NewHistogramVec(name, help string, bounds []float64, labelNames ...string) *HistogramVec {
+  return &HistogramVec{r.register("histogram", name, help, labelNames, func() interface{} {
+    return newHistogram(bounds)
+  })}
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labels formats the label pairs, with the extra pair appended when given, e.g. the le bound of a bucket.
+func labels(names, values []string, extra ...string) string {
+  var pairs []string
+  for i, name := range names {
+    pairs = append(pairs, name+`="`+labelValueReplacer.Replace(values[i])+`"`)
+  }
+  if len(extra) == 2 {
+    pairs = append(pairs, extra[0]+`="`+extra[1]+`"`)
+  }
+  if len(pairs) == 0 {
+    return ""
+  }
+  return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatFloat(value float64) string {
+  return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// write writes the family out, skipping the ones without metrics, e.g. of the daemons not running in the process.
+func (f *family) write(w *bufio.Writer) {
+  v := f.vec
+  metrics := v.sorted()
+  if len(metrics) == 0 {
+    return
+  }
+  fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", v.name, v.help, v.name, f.kind)
+  for _, m := range metrics {
+    switch metric := m.metric.(type) {
+    case *Counter:
+      fmt.Fprintf(w, "%s%s %d\n", v.name, labels(v.labelNames, m.labelValues), metric.Value())
+    case *Gauge:
+      fmt.Fprintf(w, "%s%s %s\n", v.name, labels(v.labelNames, m.labelValues), formatFloat(metric.Value()))
+    case *Histogram:
+      cumulativeCount := uint64(0)
+      for i := range metric.counts {
+        cumulativeCount += atomic.LoadUint64(&metric.counts[i])
+        bound := "+Inf"
+        if i < len(metric.bounds) {
+          bound = formatFloat(metric.bounds[i])
+        }
+        fmt.Fprintf(w, "%s_bucket%s %d\n", v.name, labels(v.labelNames, m.labelValues, "le", bound), cumulativeCount)
+      }
+      sum := formatFloat(math.Float64frombits(atomic.LoadUint64(&metric.sumBits)))
+      fmt.Fprintf(w, "%s_sum%s %s\n", v.name, labels(v.labelNames, m.labelValues), sum)
+      fmt.Fprintf(w, "%s_count%s %d\n", v.name, labels(v.labelNames, m.labelValues), cumulativeCount)
+    }
+  }
+}
+
+func (r *Registry) HandleMetricsRequest(w http.ResponseWriter, _ *http.Request) {
+  w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+  w.WriteHeader(http.StatusOK)
+  r.mutex.Lock()
+  families := append([]*family{}, r.families...)
+  r.mutex.Unlock()
+  bw := bufio.NewWriter(w)
+  for _, f := range families {
+    f.write(bw)
+  }
+  if err := bw.Flush(); err != nil {
+    log.Printf("cannot write the metrics: %v", err)
+  }
+}
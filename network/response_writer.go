@@ -4,6 +4,7 @@ import (
   "bytes"
   "encoding/json"
   "fmt"
+  "google.golang.org/protobuf/proto"
   "log"
   "net/http"
 )
@@ -21,6 +22,14 @@ func ReportServerError(w http.ResponseWriter, message string) {
   }
 }
 
+func ReportBadRequest(w http.ResponseWriter, message string) {
+  WriteCORSHeaders(w)
+  w.WriteHeader(http.StatusBadRequest)
+  if _, err := w.Write([]byte(message)); err != nil {
+    log.Printf("cannot write a message: %v", err)
+  }
+}
+
 func ReportSuccessMessage(w http.ResponseWriter, message string) {
   WriteCORSHeaders(w)
   w.WriteHeader(http.StatusOK)
@@ -29,7 +38,37 @@ func ReportSuccessMessage(w http.ResponseWriter, message string) {
   }
 }
 
+// RequirePost rejects the requests changing the daemon state which are not POSTed, so that a crawler or
+// a prefetching proxy following a link cannot change it. It tells whether the request may go on.
+func RequirePost(w http.ResponseWriter, r *http.Request) bool {
+  if r.Method == http.MethodPost {
+    return true
+  }
+  WriteCORSHeaders(w)
+  w.Header().Set("Allow", http.MethodPost)
+  http.Error(w, "expects a POST request", http.StatusMethodNotAllowed)
+  return false
+}
+
+// ReportHealth answers the plain health checks: OK when the component is ready, 503 otherwise.
+func ReportHealth(w http.ResponseWriter, ready bool) {
+  if !ready {
+    WriteCORSHeaders(w)
+    w.WriteHeader(http.StatusServiceUnavailable)
+    if _, err := w.Write([]byte("NOT READY")); err != nil {
+      log.Printf("cannot write a message: %v", err)
+    }
+    return
+  }
+  ReportSuccessMessage(w, "OK")
+}
+
 func ReportSuccessData(w http.ResponseWriter, data interface{}) {
+  ReportData(w, http.StatusOK, data)
+}
+
+// ReportData writes the data as indented json with the given status code.
+func ReportData(w http.ResponseWriter, statusCode int, data interface{}) {
   j, err := json.Marshal(data)
   if err != nil {
     ReportServerError(w, fmt.Sprintf("%v", err))
@@ -41,8 +80,22 @@ func ReportSuccessData(w http.ResponseWriter, data interface{}) {
     return
   }
   WriteCORSHeaders(w)
-  w.WriteHeader(http.StatusOK)
+  w.WriteHeader(statusCode)
   if _, err := w.Write(b.Bytes()); err != nil {
     log.Printf("cannot write a message: %v", err)
   }
 }
+
+func ReportSuccessProto(w http.ResponseWriter, contentType string, m proto.Message) {
+  b, err := proto.Marshal(m)
+  if err != nil {
+    ReportServerError(w, fmt.Sprintf("%v", err))
+    return
+  }
+  WriteCORSHeaders(w)
+  w.Header().Set("Content-Type", contentType)
+  w.WriteHeader(http.StatusOK)
+  if _, err := w.Write(b); err != nil {
+    log.Printf("cannot write a message: %v", err)
+  }
+}
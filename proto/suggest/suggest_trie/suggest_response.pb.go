@@ -0,0 +1,535 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.21.5
+// source: proto/suggest_response.proto
+
+package suggest_trie
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SuggestionTextBlock struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Text      string `protobuf:"bytes,1,opt,name=Text,proto3" json:"Text,omitempty"`
+	Highlight bool   `protobuf:"varint,2,opt,name=Highlight,proto3" json:"Highlight,omitempty"`
+}
+
+func (x *SuggestionTextBlock) Reset() {
+	*x = SuggestionTextBlock{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_suggest_response_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SuggestionTextBlock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestionTextBlock) ProtoMessage() {}
+
+func (x *SuggestionTextBlock) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_suggest_response_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestionTextBlock.ProtoReflect.Descriptor instead.
+func (*SuggestionTextBlock) Descriptor() ([]byte, []int) {
+	return file_proto_suggest_response_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SuggestionTextBlock) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *SuggestionTextBlock) GetHighlight() bool {
+	if x != nil {
+		return x.Highlight
+	}
+	return false
+}
+
+type Features struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Weight        float64 `protobuf:"fixed64,1,opt,name=Weight,proto3" json:"Weight,omitempty"`
+	LogWeight     float64 `protobuf:"fixed64,2,opt,name=LogWeight,proto3" json:"LogWeight,omitempty"`
+	ExactMatch    float64 `protobuf:"fixed64,3,opt,name=ExactMatch,proto3" json:"ExactMatch,omitempty"`
+	PrefixMatch   float64 `protobuf:"fixed64,4,opt,name=PrefixMatch,proto3" json:"PrefixMatch,omitempty"`
+	WordBoundary  float64 `protobuf:"fixed64,5,opt,name=WordBoundary,proto3" json:"WordBoundary,omitempty"`
+	MatchPosition float64 `protobuf:"fixed64,6,opt,name=MatchPosition,proto3" json:"MatchPosition,omitempty"`
+	LengthRatio   float64 `protobuf:"fixed64,7,opt,name=LengthRatio,proto3" json:"LengthRatio,omitempty"`
+	ClassPriority float64 `protobuf:"fixed64,8,opt,name=ClassPriority,proto3" json:"ClassPriority,omitempty"`
+}
+
+func (x *Features) Reset() {
+	*x = Features{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_suggest_response_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Features) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Features) ProtoMessage() {}
+
+func (x *Features) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_suggest_response_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Features.ProtoReflect.Descriptor instead.
+func (*Features) Descriptor() ([]byte, []int) {
+	return file_proto_suggest_response_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Features) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *Features) GetLogWeight() float64 {
+	if x != nil {
+		return x.LogWeight
+	}
+	return 0
+}
+
+func (x *Features) GetExactMatch() float64 {
+	if x != nil {
+		return x.ExactMatch
+	}
+	return 0
+}
+
+func (x *Features) GetPrefixMatch() float64 {
+	if x != nil {
+		return x.PrefixMatch
+	}
+	return 0
+}
+
+func (x *Features) GetWordBoundary() float64 {
+	if x != nil {
+		return x.WordBoundary
+	}
+	return 0
+}
+
+func (x *Features) GetMatchPosition() float64 {
+	if x != nil {
+		return x.MatchPosition
+	}
+	return 0
+}
+
+func (x *Features) GetLengthRatio() float64 {
+	if x != nil {
+		return x.LengthRatio
+	}
+	return 0
+}
+
+func (x *Features) GetClassPriority() float64 {
+	if x != nil {
+		return x.ClassPriority
+	}
+	return 0
+}
+
+type SuggestAnswerItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Weight       float32                `protobuf:"fixed32,1,opt,name=Weight,proto3" json:"Weight,omitempty"`
+	Data         *structpb.Struct       `protobuf:"bytes,2,opt,name=Data,proto3" json:"Data,omitempty"`
+	TextBlocks   []*SuggestionTextBlock `protobuf:"bytes,3,rep,name=TextBlocks,proto3" json:"TextBlocks,omitempty"`
+	MatchType    MatchType              `protobuf:"varint,4,opt,name=MatchType,proto3,enum=suggest_trie.MatchType" json:"MatchType,omitempty"`
+	ItemId       string                 `protobuf:"bytes,5,opt,name=ItemId,proto3" json:"ItemId,omitempty"`
+	OriginalText string                 `protobuf:"bytes,6,opt,name=OriginalText,proto3" json:"OriginalText,omitempty"`
+	Features     *Features              `protobuf:"bytes,7,opt,name=Features,proto3" json:"Features,omitempty"`
+}
+
+func (x *SuggestAnswerItem) Reset() {
+	*x = SuggestAnswerItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_suggest_response_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SuggestAnswerItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestAnswerItem) ProtoMessage() {}
+
+func (x *SuggestAnswerItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_suggest_response_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestAnswerItem.ProtoReflect.Descriptor instead.
+func (*SuggestAnswerItem) Descriptor() ([]byte, []int) {
+	return file_proto_suggest_response_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SuggestAnswerItem) GetWeight() float32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *SuggestAnswerItem) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *SuggestAnswerItem) GetTextBlocks() []*SuggestionTextBlock {
+	if x != nil {
+		return x.TextBlocks
+	}
+	return nil
+}
+
+func (x *SuggestAnswerItem) GetMatchType() MatchType {
+	if x != nil {
+		return x.MatchType
+	}
+	return MatchType_PrefixMatch
+}
+
+func (x *SuggestAnswerItem) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *SuggestAnswerItem) GetOriginalText() string {
+	if x != nil {
+		return x.OriginalText
+	}
+	return ""
+}
+
+func (x *SuggestAnswerItem) GetFeatures() *Features {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+type PaginatedSuggestResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Suggestions     []*SuggestAnswerItem `protobuf:"bytes,1,rep,name=Suggestions,proto3" json:"Suggestions,omitempty"`
+	PageNumber      int32                `protobuf:"varint,2,opt,name=PageNumber,proto3" json:"PageNumber,omitempty"`
+	TotalPagesCount int32                `protobuf:"varint,3,opt,name=TotalPagesCount,proto3" json:"TotalPagesCount,omitempty"`
+	TotalItemsCount int32                `protobuf:"varint,4,opt,name=TotalItemsCount,proto3" json:"TotalItemsCount,omitempty"`
+	NextCursor      string               `protobuf:"bytes,5,opt,name=NextCursor,proto3" json:"NextCursor,omitempty"`
+}
+
+func (x *PaginatedSuggestResponse) Reset() {
+	*x = PaginatedSuggestResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_suggest_response_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PaginatedSuggestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaginatedSuggestResponse) ProtoMessage() {}
+
+func (x *PaginatedSuggestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_suggest_response_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaginatedSuggestResponse.ProtoReflect.Descriptor instead.
+func (*PaginatedSuggestResponse) Descriptor() ([]byte, []int) {
+	return file_proto_suggest_response_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PaginatedSuggestResponse) GetSuggestions() []*SuggestAnswerItem {
+	if x != nil {
+		return x.Suggestions
+	}
+	return nil
+}
+
+func (x *PaginatedSuggestResponse) GetPageNumber() int32 {
+	if x != nil {
+		return x.PageNumber
+	}
+	return 0
+}
+
+func (x *PaginatedSuggestResponse) GetTotalPagesCount() int32 {
+	if x != nil {
+		return x.TotalPagesCount
+	}
+	return 0
+}
+
+func (x *PaginatedSuggestResponse) GetTotalItemsCount() int32 {
+	if x != nil {
+		return x.TotalItemsCount
+	}
+	return 0
+}
+
+func (x *PaginatedSuggestResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+var File_proto_suggest_response_proto protoreflect.FileDescriptor
+
+var file_proto_suggest_response_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x5f,
+	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c,
+	0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x74, 0x72, 0x69, 0x65, 0x1a, 0x1c, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74,
+	0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x18, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2f, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x74, 0x72, 0x69, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x47, 0x0a, 0x13, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69,
+	0x6f, 0x6e, 0x54, 0x65, 0x78, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x54,
+	0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x54, 0x65, 0x78, 0x74, 0x12,
+	0x1c, 0x0a, 0x09, 0x48, 0x69, 0x67, 0x68, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x09, 0x48, 0x69, 0x67, 0x68, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x22, 0x94, 0x02,
+	0x0a, 0x08, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x57, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x57, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x4c, 0x6f, 0x67, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x4c, 0x6f, 0x67, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x12, 0x1e, 0x0a, 0x0a, 0x45, 0x78, 0x61, 0x63, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x45, 0x78, 0x61, 0x63, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68,
+	0x12, 0x20, 0x0a, 0x0b, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x12, 0x22, 0x0a, 0x0c, 0x57, 0x6f, 0x72, 0x64, 0x42, 0x6f, 0x75, 0x6e, 0x64, 0x61,
+	0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x57, 0x6f, 0x72, 0x64, 0x42, 0x6f,
+	0x75, 0x6e, 0x64, 0x61, 0x72, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x50,
+	0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x4d,
+	0x61, 0x74, 0x63, 0x68, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b,
+	0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x52, 0x61, 0x74, 0x69, 0x6f, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0b, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x52, 0x61, 0x74, 0x69, 0x6f, 0x12, 0x24,
+	0x0a, 0x0d, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x50, 0x72, 0x69, 0x6f,
+	0x72, 0x69, 0x74, 0x79, 0x22, 0xc2, 0x02, 0x0a, 0x11, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74,
+	0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x57, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x02, 0x52, 0x06, 0x57, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x12, 0x2b, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x04, 0x44, 0x61, 0x74, 0x61, 0x12,
+	0x41, 0x0a, 0x0a, 0x54, 0x65, 0x78, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x74, 0x72,
+	0x69, 0x65, 0x2e, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x65, 0x78,
+	0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x0a, 0x54, 0x65, 0x78, 0x74, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x73, 0x12, 0x35, 0x0a, 0x09, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x54, 0x79, 0x70, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x5f,
+	0x74, 0x72, 0x69, 0x65, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09,
+	0x4d, 0x61, 0x74, 0x63, 0x68, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x49, 0x74, 0x65,
+	0x6d, 0x49, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x49, 0x74, 0x65, 0x6d, 0x49,
+	0x64, 0x12, 0x22, 0x0a, 0x0c, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x54, 0x65, 0x78,
+	0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61,
+	0x6c, 0x54, 0x65, 0x78, 0x74, 0x12, 0x32, 0x0a, 0x08, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73,
+	0x74, 0x5f, 0x74, 0x72, 0x69, 0x65, 0x2e, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52,
+	0x08, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x22, 0xf1, 0x01, 0x0a, 0x18, 0x50, 0x61,
+	0x67, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x64, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a, 0x0b, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x73, 0x75,
+	0x67, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x74, 0x72, 0x69, 0x65, 0x2e, 0x53, 0x75, 0x67, 0x67, 0x65,
+	0x73, 0x74, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x0b, 0x53, 0x75,
+	0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x50, 0x61, 0x67,
+	0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x50,
+	0x61, 0x67, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x28, 0x0a, 0x0f, 0x54, 0x6f, 0x74,
+	0x61, 0x6c, 0x50, 0x61, 0x67, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0f, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x50, 0x61, 0x67, 0x65, 0x73, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x12, 0x28, 0x0a, 0x0f, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x49, 0x74, 0x65, 0x6d,
+	0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x54, 0x6f,
+	0x74, 0x61, 0x6c, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1e, 0x0a,
+	0x0a, 0x4e, 0x65, 0x78, 0x74, 0x43, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x4e, 0x65, 0x78, 0x74, 0x43, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x42, 0x16, 0x5a,
+	0x14, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x2f, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74,
+	0x5f, 0x74, 0x72, 0x69, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_suggest_response_proto_rawDescOnce sync.Once
+	file_proto_suggest_response_proto_rawDescData = file_proto_suggest_response_proto_rawDesc
+)
+
+func file_proto_suggest_response_proto_rawDescGZIP() []byte {
+	file_proto_suggest_response_proto_rawDescOnce.Do(func() {
+		file_proto_suggest_response_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_suggest_response_proto_rawDescData)
+	})
+	return file_proto_suggest_response_proto_rawDescData
+}
+
+var file_proto_suggest_response_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_suggest_response_proto_goTypes = []interface{}{
+	(*SuggestionTextBlock)(nil),      // 0: suggest_trie.SuggestionTextBlock
+	(*Features)(nil),                 // 1: suggest_trie.Features
+	(*SuggestAnswerItem)(nil),        // 2: suggest_trie.SuggestAnswerItem
+	(*PaginatedSuggestResponse)(nil), // 3: suggest_trie.PaginatedSuggestResponse
+	(*structpb.Struct)(nil),          // 4: google.protobuf.Struct
+	(MatchType)(0),                   // 5: suggest_trie.MatchType
+}
+var file_proto_suggest_response_proto_depIdxs = []int32{
+	4, // 0: suggest_trie.SuggestAnswerItem.Data:type_name -> google.protobuf.Struct
+	0, // 1: suggest_trie.SuggestAnswerItem.TextBlocks:type_name -> suggest_trie.SuggestionTextBlock
+	5, // 2: suggest_trie.SuggestAnswerItem.MatchType:type_name -> suggest_trie.MatchType
+	1, // 3: suggest_trie.SuggestAnswerItem.Features:type_name -> suggest_trie.Features
+	2, // 4: suggest_trie.PaginatedSuggestResponse.Suggestions:type_name -> suggest_trie.SuggestAnswerItem
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_proto_suggest_response_proto_init() }
+func file_proto_suggest_response_proto_init() {
+	if File_proto_suggest_response_proto != nil {
+		return
+	}
+	file_proto_suggest_trie_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_proto_suggest_response_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SuggestionTextBlock); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_suggest_response_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Features); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_suggest_response_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SuggestAnswerItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_suggest_response_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PaginatedSuggestResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_suggest_response_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_proto_suggest_response_proto_goTypes,
+		DependencyIndexes: file_proto_suggest_response_proto_depIdxs,
+		MessageInfos:      file_proto_suggest_response_proto_msgTypes,
+	}.Build()
+	File_proto_suggest_response_proto = out.File
+	file_proto_suggest_response_proto_rawDesc = nil
+	file_proto_suggest_response_proto_goTypes = nil
+	file_proto_suggest_response_proto_depIdxs = nil
+}
@@ -0,0 +1,220 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.5
+// source: proto/suggest_service.proto
+
+package suggest_trie
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	SuggestService_Suggest_FullMethodName      = "/suggest_trie.SuggestService/Suggest"
+	SuggestService_BatchSuggest_FullMethodName = "/suggest_trie.SuggestService/BatchSuggest"
+	SuggestService_Health_FullMethodName       = "/suggest_trie.SuggestService/Health"
+	SuggestService_GetItem_FullMethodName      = "/suggest_trie.SuggestService/GetItem"
+)
+
+// SuggestServiceClient is the client API for SuggestService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SuggestServiceClient interface {
+	Suggest(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*PaginatedSuggestResponse, error)
+	BatchSuggest(ctx context.Context, in *BatchSuggestRequest, opts ...grpc.CallOption) (*BatchSuggestResponse, error)
+	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
+	GetItem(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*Item, error)
+}
+
+type suggestServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSuggestServiceClient(cc grpc.ClientConnInterface) SuggestServiceClient {
+	return &suggestServiceClient{cc}
+}
+
+func (c *suggestServiceClient) Suggest(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*PaginatedSuggestResponse, error) {
+	out := new(PaginatedSuggestResponse)
+	err := c.cc.Invoke(ctx, SuggestService_Suggest_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *suggestServiceClient) BatchSuggest(ctx context.Context, in *BatchSuggestRequest, opts ...grpc.CallOption) (*BatchSuggestResponse, error) {
+	out := new(BatchSuggestResponse)
+	err := c.cc.Invoke(ctx, SuggestService_BatchSuggest_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *suggestServiceClient) Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error) {
+	out := new(HealthResponse)
+	err := c.cc.Invoke(ctx, SuggestService_Health_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *suggestServiceClient) GetItem(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*Item, error) {
+	out := new(Item)
+	err := c.cc.Invoke(ctx, SuggestService_GetItem_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SuggestServiceServer is the server API for SuggestService service.
+// All implementations must embed UnimplementedSuggestServiceServer
+// for forward compatibility
+type SuggestServiceServer interface {
+	Suggest(context.Context, *SuggestRequest) (*PaginatedSuggestResponse, error)
+	BatchSuggest(context.Context, *BatchSuggestRequest) (*BatchSuggestResponse, error)
+	Health(context.Context, *HealthRequest) (*HealthResponse, error)
+	GetItem(context.Context, *GetItemRequest) (*Item, error)
+	mustEmbedUnimplementedSuggestServiceServer()
+}
+
+// UnimplementedSuggestServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedSuggestServiceServer struct {
+}
+
+func (UnimplementedSuggestServiceServer) Suggest(context.Context, *SuggestRequest) (*PaginatedSuggestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Suggest not implemented")
+}
+func (UnimplementedSuggestServiceServer) BatchSuggest(context.Context, *BatchSuggestRequest) (*BatchSuggestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchSuggest not implemented")
+}
+func (UnimplementedSuggestServiceServer) Health(context.Context, *HealthRequest) (*HealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Health not implemented")
+}
+func (UnimplementedSuggestServiceServer) GetItem(context.Context, *GetItemRequest) (*Item, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetItem not implemented")
+}
+func (UnimplementedSuggestServiceServer) mustEmbedUnimplementedSuggestServiceServer() {}
+
+// UnsafeSuggestServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SuggestServiceServer will
+// result in compilation errors.
+type UnsafeSuggestServiceServer interface {
+	mustEmbedUnimplementedSuggestServiceServer()
+}
+
+func RegisterSuggestServiceServer(s grpc.ServiceRegistrar, srv SuggestServiceServer) {
+	s.RegisterService(&SuggestService_ServiceDesc, srv)
+}
+
+func _SuggestService_Suggest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SuggestServiceServer).Suggest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SuggestService_Suggest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SuggestServiceServer).Suggest(ctx, req.(*SuggestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SuggestService_BatchSuggest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchSuggestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SuggestServiceServer).BatchSuggest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SuggestService_BatchSuggest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SuggestServiceServer).BatchSuggest(ctx, req.(*BatchSuggestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SuggestService_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SuggestServiceServer).Health(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SuggestService_Health_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SuggestServiceServer).Health(ctx, req.(*HealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SuggestService_GetItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SuggestServiceServer).GetItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SuggestService_GetItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SuggestServiceServer).GetItem(ctx, req.(*GetItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SuggestService_ServiceDesc is the grpc.ServiceDesc for SuggestService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SuggestService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "suggest_trie.SuggestService",
+	HandlerType: (*SuggestServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Suggest",
+			Handler:    _SuggestService_Suggest_Handler,
+		},
+		{
+			MethodName: "BatchSuggest",
+			Handler:    _SuggestService_BatchSuggest_Handler,
+		},
+		{
+			MethodName: "Health",
+			Handler:    _SuggestService_Health_Handler,
+		},
+		{
+			MethodName: "GetItem",
+			Handler:    _SuggestService_GetItem_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/suggest_service.proto",
+}
@@ -21,14 +21,64 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type MatchType int32
+
+const (
+	MatchType_PrefixMatch MatchType = 0
+	MatchType_SuffixMatch MatchType = 1
+)
+
+// Enum value maps for MatchType.
+var (
+	MatchType_name = map[int32]string{
+		0: "PrefixMatch",
+		1: "SuffixMatch",
+	}
+	MatchType_value = map[string]int32{
+		"PrefixMatch": 0,
+		"SuffixMatch": 1,
+	}
+)
+
+func (x MatchType) Enum() *MatchType {
+	p := new(MatchType)
+	*p = x
+	return p
+}
+
+func (x MatchType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MatchType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_suggest_trie_proto_enumTypes[0].Descriptor()
+}
+
+func (MatchType) Type() protoreflect.EnumType {
+	return &file_proto_suggest_trie_proto_enumTypes[0]
+}
+
+func (x MatchType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MatchType.Descriptor instead.
+func (MatchType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_suggest_trie_proto_rawDescGZIP(), []int{0}
+}
+
 type Item struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Weight       float32          `protobuf:"fixed32,1,opt,name=Weight,proto3" json:"Weight,omitempty"`
-	OriginalText string           `protobuf:"bytes,2,opt,name=OriginalText,proto3" json:"OriginalText,omitempty"`
-	Data         *structpb.Struct `protobuf:"bytes,4,opt,name=Data,proto3" json:"Data,omitempty"`
+	Weight         float32            `protobuf:"fixed32,1,opt,name=Weight,proto3" json:"Weight,omitempty"`
+	OriginalText   string             `protobuf:"bytes,2,opt,name=OriginalText,proto3" json:"OriginalText,omitempty"`
+	Data           *structpb.Struct   `protobuf:"bytes,4,opt,name=Data,proto3" json:"Data,omitempty"`
+	Facets         map[string]string  `protobuf:"bytes,5,rep,name=Facets,proto3" json:"Facets,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	NumericFacets  map[string]float64 `protobuf:"bytes,6,rep,name=NumericFacets,proto3" json:"NumericFacets,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	StoredData     *structpb.Struct   `protobuf:"bytes,7,opt,name=StoredData,proto3" json:"StoredData,omitempty"`
+	NormalizedText string             `protobuf:"bytes,8,opt,name=NormalizedText,proto3" json:"NormalizedText,omitempty"`
 }
 
 func (x *Item) Reset() {
@@ -84,15 +134,44 @@ func (x *Item) GetData() *structpb.Struct {
 	return nil
 }
 
+func (x *Item) GetFacets() map[string]string {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
+
+func (x *Item) GetNumericFacets() map[string]float64 {
+	if x != nil {
+		return x.NumericFacets
+	}
+	return nil
+}
+
+func (x *Item) GetStoredData() *structpb.Struct {
+	if x != nil {
+		return x.StoredData
+	}
+	return nil
+}
+
+func (x *Item) GetNormalizedText() string {
+	if x != nil {
+		return x.NormalizedText
+	}
+	return ""
+}
+
 type ClassItems struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Class       string    `protobuf:"bytes,1,opt,name=Class,proto3" json:"Class,omitempty"`
-	ItemWeights []float32 `protobuf:"fixed32,2,rep,packed,name=ItemWeights,proto3" json:"ItemWeights,omitempty"`
-	ItemIndexes []uint32  `protobuf:"varint,3,rep,packed,name=ItemIndexes,proto3" json:"ItemIndexes,omitempty"`
-	Classes     []string  `protobuf:"bytes,4,rep,name=Classes,proto3" json:"Classes,omitempty"`
+	Class          string      `protobuf:"bytes,1,opt,name=Class,proto3" json:"Class,omitempty"`
+	ItemWeights    []float32   `protobuf:"fixed32,2,rep,packed,name=ItemWeights,proto3" json:"ItemWeights,omitempty"`
+	ItemIndexes    []uint32    `protobuf:"varint,3,rep,packed,name=ItemIndexes,proto3" json:"ItemIndexes,omitempty"`
+	Classes        []string    `protobuf:"bytes,4,rep,name=Classes,proto3" json:"Classes,omitempty"`
+	ItemMatchTypes []MatchType `protobuf:"varint,5,rep,packed,name=ItemMatchTypes,proto3,enum=suggest_trie.MatchType" json:"ItemMatchTypes,omitempty"`
 }
 
 func (x *ClassItems) Reset() {
@@ -155,6 +234,13 @@ func (x *ClassItems) GetClasses() []string {
 	return nil
 }
 
+func (x *ClassItems) GetItemMatchTypes() []MatchType {
+	if x != nil {
+		return x.ItemMatchTypes
+	}
+	return nil
+}
+
 type SuggestTrie struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -163,6 +249,7 @@ type SuggestTrie struct {
 	DescendantKeys  []uint32       `protobuf:"varint,1,rep,packed,name=DescendantKeys,proto3" json:"DescendantKeys,omitempty"`
 	DescendantTries []*SuggestTrie `protobuf:"bytes,2,rep,name=DescendantTries,proto3" json:"DescendantTries,omitempty"`
 	Items           []*ClassItems  `protobuf:"bytes,4,rep,name=Items,proto3" json:"Items,omitempty"`
+	TotalItemsCount uint32         `protobuf:"varint,5,opt,name=TotalItemsCount,proto3" json:"TotalItemsCount,omitempty"`
 }
 
 func (x *SuggestTrie) Reset() {
@@ -218,14 +305,23 @@ func (x *SuggestTrie) GetItems() []*ClassItems {
 	return nil
 }
 
+func (x *SuggestTrie) GetTotalItemsCount() uint32 {
+	if x != nil {
+		return x.TotalItemsCount
+	}
+	return 0
+}
+
 type SuggestData struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Trie    *SuggestTrie `protobuf:"bytes,1,opt,name=Trie,proto3" json:"Trie,omitempty"`
-	Items   []*Item      `protobuf:"bytes,2,rep,name=Items,proto3" json:"Items,omitempty"`
-	Version uint64       `protobuf:"varint,3,opt,name=Version,proto3" json:"Version,omitempty"`
+	Trie             *SuggestTrie `protobuf:"bytes,1,opt,name=Trie,proto3" json:"Trie,omitempty"`
+	Items            []*Item      `protobuf:"bytes,2,rep,name=Items,proto3" json:"Items,omitempty"`
+	Version          uint64       `protobuf:"varint,3,opt,name=Version,proto3" json:"Version,omitempty"`
+	Facets           []string     `protobuf:"bytes,4,rep,name=Facets,proto3" json:"Facets,omitempty"`
+	StoredOnlyFields []string     `protobuf:"bytes,5,rep,name=StoredOnlyFields,proto3" json:"StoredOnlyFields,omitempty"`
 }
 
 func (x *SuggestData) Reset() {
@@ -281,6 +377,20 @@ func (x *SuggestData) GetVersion() uint64 {
 	return 0
 }
 
+func (x *SuggestData) GetFacets() []string {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
+
+func (x *SuggestData) GetStoredOnlyFields() []string {
+	if x != nil {
+		return x.StoredOnlyFields
+	}
+	return nil
+}
+
 var File_proto_suggest_trie_proto protoreflect.FileDescriptor
 
 var file_proto_suggest_trie_proto_rawDesc = []byte{
@@ -288,43 +398,80 @@ var file_proto_suggest_trie_proto_rawDesc = []byte{
 	0x74, 0x72, 0x69, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x73, 0x75, 0x67, 0x67,
 	0x65, 0x73, 0x74, 0x5f, 0x74, 0x72, 0x69, 0x65, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
 	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x6f, 0x0a, 0x04, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x16,
-	0x0a, 0x06, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x02, 0x52, 0x06,
-	0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e,
-	0x61, 0x6c, 0x54, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x4f, 0x72,
-	0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x54, 0x65, 0x78, 0x74, 0x12, 0x2b, 0x0a, 0x04, 0x44, 0x61,
-	0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63,
-	0x74, 0x52, 0x04, 0x44, 0x61, 0x74, 0x61, 0x22, 0x80, 0x01, 0x0a, 0x0a, 0x43, 0x6c, 0x61, 0x73,
-	0x73, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x20, 0x0a, 0x0b,
-	0x49, 0x74, 0x65, 0x6d, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
-	0x02, 0x52, 0x0b, 0x49, 0x74, 0x65, 0x6d, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73, 0x12, 0x20,
-	0x0a, 0x0b, 0x49, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x73, 0x18, 0x03, 0x20,
-	0x03, 0x28, 0x0d, 0x52, 0x0b, 0x49, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x73,
-	0x12, 0x18, 0x0a, 0x07, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
-	0x09, 0x52, 0x07, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x65, 0x73, 0x22, 0xaa, 0x01, 0x0a, 0x0b, 0x53,
-	0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x54, 0x72, 0x69, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x44, 0x65,
-	0x73, 0x63, 0x65, 0x6e, 0x64, 0x61, 0x6e, 0x74, 0x4b, 0x65, 0x79, 0x73, 0x18, 0x01, 0x20, 0x03,
-	0x28, 0x0d, 0x52, 0x0e, 0x44, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x61, 0x6e, 0x74, 0x4b, 0x65,
-	0x79, 0x73, 0x12, 0x43, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x61, 0x6e, 0x74,
-	0x54, 0x72, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x75,
-	0x67, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x74, 0x72, 0x69, 0x65, 0x2e, 0x53, 0x75, 0x67, 0x67, 0x65,
-	0x73, 0x74, 0x54, 0x72, 0x69, 0x65, 0x52, 0x0f, 0x44, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x61,
-	0x6e, 0x74, 0x54, 0x72, 0x69, 0x65, 0x73, 0x12, 0x2e, 0x0a, 0x05, 0x49, 0x74, 0x65, 0x6d, 0x73,
-	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74,
-	0x5f, 0x74, 0x72, 0x69, 0x65, 0x2e, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x49, 0x74, 0x65, 0x6d, 0x73,
-	0x52, 0x05, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x80, 0x01, 0x0a, 0x0b, 0x53, 0x75, 0x67, 0x67,
-	0x65, 0x73, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x2d, 0x0a, 0x04, 0x54, 0x72, 0x69, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x5f,
-	0x74, 0x72, 0x69, 0x65, 0x2e, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x54, 0x72, 0x69, 0x65,
-	0x52, 0x04, 0x54, 0x72, 0x69, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18,
-	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x5f,
-	0x74, 0x72, 0x69, 0x65, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x49, 0x74, 0x65, 0x6d, 0x73,
-	0x12, 0x18, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x16, 0x5a, 0x14, 0x73, 0x75,
-	0x67, 0x67, 0x65, 0x73, 0x74, 0x2f, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x74, 0x72,
-	0x69, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd2, 0x03, 0x0a, 0x04, 0x49, 0x74, 0x65, 0x6d, 0x12,
+	0x16, 0x0a, 0x06, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x02, 0x52,
+	0x06, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x4f, 0x72, 0x69, 0x67, 0x69,
+	0x6e, 0x61, 0x6c, 0x54, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x4f,
+	0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x54, 0x65, 0x78, 0x74, 0x12, 0x2b, 0x0a, 0x04, 0x44,
+	0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75,
+	0x63, 0x74, 0x52, 0x04, 0x44, 0x61, 0x74, 0x61, 0x12, 0x36, 0x0a, 0x06, 0x46, 0x61, 0x63, 0x65,
+	0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x73, 0x75, 0x67, 0x67, 0x65,
+	0x73, 0x74, 0x5f, 0x74, 0x72, 0x69, 0x65, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x2e, 0x46, 0x61, 0x63,
+	0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x46, 0x61, 0x63, 0x65, 0x74, 0x73,
+	0x12, 0x4b, 0x0a, 0x0d, 0x4e, 0x75, 0x6d, 0x65, 0x72, 0x69, 0x63, 0x46, 0x61, 0x63, 0x65, 0x74,
+	0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73,
+	0x74, 0x5f, 0x74, 0x72, 0x69, 0x65, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x2e, 0x4e, 0x75, 0x6d, 0x65,
+	0x72, 0x69, 0x63, 0x46, 0x61, 0x63, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d,
+	0x4e, 0x75, 0x6d, 0x65, 0x72, 0x69, 0x63, 0x46, 0x61, 0x63, 0x65, 0x74, 0x73, 0x12, 0x37, 0x0a,
+	0x0a, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x0a, 0x53, 0x74, 0x6f, 0x72,
+	0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x12, 0x26, 0x0a, 0x0e, 0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c,
+	0x69, 0x7a, 0x65, 0x64, 0x54, 0x65, 0x78, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x54, 0x65, 0x78, 0x74, 0x1a, 0x39,
+	0x0a, 0x0b, 0x46, 0x61, 0x63, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x40, 0x0a, 0x12, 0x4e, 0x75, 0x6d,
+	0x65, 0x72, 0x69, 0x63, 0x46, 0x61, 0x63, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xc1, 0x01, 0x0a, 0x0a,
+	0x43, 0x6c, 0x61, 0x73, 0x73, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x43, 0x6c,
+	0x61, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x43, 0x6c, 0x61, 0x73, 0x73,
+	0x12, 0x20, 0x0a, 0x0b, 0x49, 0x74, 0x65, 0x6d, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x02, 0x52, 0x0b, 0x49, 0x74, 0x65, 0x6d, 0x57, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x49, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x65,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x0b, 0x49, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x64,
+	0x65, 0x78, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x65, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x65, 0x73, 0x12, 0x3f,
+	0x0a, 0x0e, 0x49, 0x74, 0x65, 0x6d, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x54, 0x79, 0x70, 0x65, 0x73,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74,
+	0x5f, 0x74, 0x72, 0x69, 0x65, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x0e, 0x49, 0x74, 0x65, 0x6d, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x54, 0x79, 0x70, 0x65, 0x73, 0x22,
+	0xd4, 0x01, 0x0a, 0x0b, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x54, 0x72, 0x69, 0x65, 0x12,
+	0x26, 0x0a, 0x0e, 0x44, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x61, 0x6e, 0x74, 0x4b, 0x65, 0x79,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x0e, 0x44, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64,
+	0x61, 0x6e, 0x74, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x43, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x63, 0x65,
+	0x6e, 0x64, 0x61, 0x6e, 0x74, 0x54, 0x72, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x74, 0x72, 0x69, 0x65, 0x2e,
+	0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x54, 0x72, 0x69, 0x65, 0x52, 0x0f, 0x44, 0x65, 0x73,
+	0x63, 0x65, 0x6e, 0x64, 0x61, 0x6e, 0x74, 0x54, 0x72, 0x69, 0x65, 0x73, 0x12, 0x2e, 0x0a, 0x05,
+	0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x73, 0x75,
+	0x67, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x74, 0x72, 0x69, 0x65, 0x2e, 0x43, 0x6c, 0x61, 0x73, 0x73,
+	0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x05, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x28, 0x0a, 0x0f,
+	0x54, 0x6f, 0x74, 0x61, 0x6c, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x49, 0x74, 0x65, 0x6d,
+	0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xc4, 0x01, 0x0a, 0x0b, 0x53, 0x75, 0x67, 0x67, 0x65,
+	0x73, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x2d, 0x0a, 0x04, 0x54, 0x72, 0x69, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x74,
+	0x72, 0x69, 0x65, 0x2e, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x54, 0x72, 0x69, 0x65, 0x52,
+	0x04, 0x54, 0x72, 0x69, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x74,
+	0x72, 0x69, 0x65, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12,
+	0x18, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x46, 0x61, 0x63,
+	0x65, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x46, 0x61, 0x63, 0x65, 0x74,
+	0x73, 0x12, 0x2a, 0x0a, 0x10, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x4f, 0x6e, 0x6c, 0x79, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x10, 0x53, 0x74, 0x6f,
+	0x72, 0x65, 0x64, 0x4f, 0x6e, 0x6c, 0x79, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x2a, 0x3f, 0x0a,
+	0x09, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x50, 0x72,
+	0x65, 0x66, 0x69, 0x78, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x53,
+	0x75, 0x66, 0x66, 0x69, 0x78, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x10, 0x01, 0x22, 0x04, 0x08, 0x02,
+	0x10, 0x02, 0x2a, 0x0a, 0x4f, 0x74, 0x68, 0x65, 0x72, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x42, 0x16,
+	0x5a, 0x14, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x2f, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73,
+	0x74, 0x5f, 0x74, 0x72, 0x69, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -339,25 +486,33 @@ func file_proto_suggest_trie_proto_rawDescGZIP() []byte {
 	return file_proto_suggest_trie_proto_rawDescData
 }
 
-var file_proto_suggest_trie_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_suggest_trie_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_suggest_trie_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_proto_suggest_trie_proto_goTypes = []interface{}{
-	(*Item)(nil),            // 0: suggest_trie.Item
-	(*ClassItems)(nil),      // 1: suggest_trie.ClassItems
-	(*SuggestTrie)(nil),     // 2: suggest_trie.SuggestTrie
-	(*SuggestData)(nil),     // 3: suggest_trie.SuggestData
-	(*structpb.Struct)(nil), // 4: google.protobuf.Struct
+	(MatchType)(0),          // 0: suggest_trie.MatchType
+	(*Item)(nil),            // 1: suggest_trie.Item
+	(*ClassItems)(nil),      // 2: suggest_trie.ClassItems
+	(*SuggestTrie)(nil),     // 3: suggest_trie.SuggestTrie
+	(*SuggestData)(nil),     // 4: suggest_trie.SuggestData
+	nil,                     // 5: suggest_trie.Item.FacetsEntry
+	nil,                     // 6: suggest_trie.Item.NumericFacetsEntry
+	(*structpb.Struct)(nil), // 7: google.protobuf.Struct
 }
 var file_proto_suggest_trie_proto_depIdxs = []int32{
-	4, // 0: suggest_trie.Item.Data:type_name -> google.protobuf.Struct
-	2, // 1: suggest_trie.SuggestTrie.DescendantTries:type_name -> suggest_trie.SuggestTrie
-	1, // 2: suggest_trie.SuggestTrie.Items:type_name -> suggest_trie.ClassItems
-	2, // 3: suggest_trie.SuggestData.Trie:type_name -> suggest_trie.SuggestTrie
-	0, // 4: suggest_trie.SuggestData.Items:type_name -> suggest_trie.Item
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	7, // 0: suggest_trie.Item.Data:type_name -> google.protobuf.Struct
+	5, // 1: suggest_trie.Item.Facets:type_name -> suggest_trie.Item.FacetsEntry
+	6, // 2: suggest_trie.Item.NumericFacets:type_name -> suggest_trie.Item.NumericFacetsEntry
+	7, // 3: suggest_trie.Item.StoredData:type_name -> google.protobuf.Struct
+	0, // 4: suggest_trie.ClassItems.ItemMatchTypes:type_name -> suggest_trie.MatchType
+	3, // 5: suggest_trie.SuggestTrie.DescendantTries:type_name -> suggest_trie.SuggestTrie
+	2, // 6: suggest_trie.SuggestTrie.Items:type_name -> suggest_trie.ClassItems
+	3, // 7: suggest_trie.SuggestData.Trie:type_name -> suggest_trie.SuggestTrie
+	1, // 8: suggest_trie.SuggestData.Items:type_name -> suggest_trie.Item
+	9, // [9:9] is the sub-list for method output_type
+	9, // [9:9] is the sub-list for method input_type
+	9, // [9:9] is the sub-list for extension type_name
+	9, // [9:9] is the sub-list for extension extendee
+	0, // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_proto_suggest_trie_proto_init() }
@@ -420,13 +575,14 @@ func file_proto_suggest_trie_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_suggest_trie_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      1,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_proto_suggest_trie_proto_goTypes,
 		DependencyIndexes: file_proto_suggest_trie_proto_depIdxs,
+		EnumInfos:         file_proto_suggest_trie_proto_enumTypes,
 		MessageInfos:      file_proto_suggest_trie_proto_msgTypes,
 	}.Build()
 	File_proto_suggest_trie_proto = out.File
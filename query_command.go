@@ -0,0 +1,79 @@
+package main
+
+import (
+  "encoding/json"
+  "fmt"
+  "main/suggest"
+  "os"
+)
+
+func NewQueryCommand() *Command {
+  c := NewCommand("query", "run a suggest lookup offline against an index file and print the response json")
+  source := addSuggestSourceFlags(c)
+  equalShapedNormalize := c.Flags.Bool("equal-shaped-normalize", false, "additional normalization for cyrillic symbols")
+  part := c.Flags.String("part", "", "the query text")
+  classes := &stringsFlag{}
+  c.Flags.Var(classes, "class", "class to return suggestions of, may be repeated")
+  excludeClasses := &stringsFlag{}
+  c.Flags.Var(excludeClasses, "exclude-class", "class to skip suggestions of, may be repeated")
+  filters := &stringsFlag{}
+  c.Flags.Var(filters, "filter", "data field filter: field:value, field:value1|value2 or field:[min..max], may be repeated")
+  count := c.Flags.Int("count", 0, "number of suggestions to return, 0 for all")
+  page := c.Flags.Int("page", -1, "page number, pagination is off when negative")
+  cursor := c.Flags.String("cursor", "", "next_cursor of the previous page to continue from, turns pagination on")
+  deep := c.Flags.Bool("deep", false, "walk the prefix subtree for the matches the prefix node does not keep, ranked by the stored weights, implied by --cursor")
+  freshnessHalfLife := c.Flags.Duration("freshness-half-life", 0, "time after which the weight of an item with created_at halves, 0 disables the decay")
+  rankingModelPath := c.Flags.String("ranking-model", "", "linear ranking model json, the suggestions are ranked by their weights when empty")
+  nodesBudget := c.Flags.Int("nodes-budget", suggest.DefaultNodesBudget, "max number of trie nodes searched to fill the count after filtering, negative to disable")
+  fields := c.Flags.String("fields", "", "comma-separated fields to print: weight, text, match_type, data or data.<key>, all when empty")
+  debug := c.Flags.Bool("debug", false, "print the ranking features of every suggestion")
+
+  c.Run = func(_ []string) error {
+    h, err := source.newHandler(*equalShapedNormalize)
+    if err != nil {
+      return err
+    }
+    h.FreshnessHalfLife = *freshnessHalfLife
+    h.NodesBudget = *nodesBudget
+    if err := setRankingModel(h, *rankingModelPath); err != nil {
+      return err
+    }
+    parsedFilters, err := suggest.ParseFilters(*filters)
+    if err != nil {
+      return err
+    }
+    projection, err := suggest.ParseProjection([]string{*fields})
+    if err != nil {
+      return err
+    }
+    pagingParameters := &suggest.PagingParameters{
+      Count:        *count,
+      Page:         *page,
+      PaginationOn: *page >= 0,
+    }
+    if *cursor != "" {
+      if pagingParameters.Cursor, err = suggest.ParseCursor(*cursor); err != nil {
+        return err
+      }
+      pagingParameters.PaginationOn = true
+    }
+    q := &suggest.SuggestQuery{
+      Part:           *part,
+      Classes:        *classes,
+      ExcludeClasses: *excludeClasses,
+      Filters:        parsedFilters,
+      Debug:          *debug,
+      Deep:           *deep,
+    }
+    h.SetPaging(q, pagingParameters)
+    response := pagingParameters.Paginate(h.GetSuggest(q))
+    projection.Apply(response.Suggestions)
+    b, err := json.MarshalIndent(response, "", "  ")
+    if err != nil {
+      return err
+    }
+    _, err = fmt.Fprintln(os.Stdout, string(b))
+    return err
+  }
+  return c
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+  "fmt"
+  "google.golang.org/grpc"
+  "log"
+  "main/access_log"
+  "main/metrics"
+  stpb "main/proto/suggest/suggest_trie"
+  "main/suggest"
+  "main/suggest_feedback"
+  "main/suggest_grpc"
+  "main/suggest_personalization"
+  "main/tools"
+  "net/http"
+  "time"
+)
+
+// suggestSource is the flags every command reading a single suggest index accepts.
+type suggestSource struct {
+  suggestDataPath *string
+  manifestPath    *string
+  shard           *int
+}
+
+func addSuggestSourceFlags(c *Command) *suggestSource {
+  return &suggestSource{
+    suggestDataPath: c.Flags.String("suggest", "", "suggest data file path"),
+    manifestPath:    c.Flags.String("manifest", "", "build manifest or build output directory, used instead of --suggest"),
+    shard:           c.Flags.Int("shard", -1, "number of the shard to read from a sharded --manifest, e.g. to serve it as a remote shard of the merger"),
+  }
+}
+
+func (ss *suggestSource) readManifest() (*suggest.Manifest, error) {
+  if *ss.manifestPath == "" {
+    if *ss.suggestDataPath == "" {
+      return nil, fmt.Errorf("please specify the suggest data path via the --suggest or --manifest parameter")
+    }
+    return nil, nil
+  }
+  return suggest.ReadManifest(*ss.manifestPath)
+}
+
+func (ss *suggestSource) load() (*stpb.SuggestData, *suggest.Manifest, error) {
+  manifest, err := ss.readManifest()
+  if err != nil {
+    return nil, nil, err
+  }
+  if manifest == nil {
+    suggestData, err := suggest.LoadSuggest(*ss.suggestDataPath)
+    return suggestData, nil, err
+  }
+  file := manifest.Files[0]
+  if *ss.shard >= 0 {
+    if *ss.shard >= len(manifest.Files) {
+      return nil, nil, fmt.Errorf("no shard %d in the manifest, it lists %d files", *ss.shard, len(manifest.Files))
+    }
+    file = manifest.Files[*ss.shard]
+  } else if manifest.Sharding != nil || len(manifest.Files) != 1 {
+    return nil, nil, fmt.Errorf("the manifest describes a sharded build, pick a shard with --shard or use the merge command")
+  }
+  suggestData, err := manifest.LoadFile(file)
+  return suggestData, manifest, err
+}
+
+// accessLogFlags are the flags of the daemons writing the access log.
+type accessLogFlags struct {
+  path       *string
+  maxSize    *int64
+  maxFiles   *int
+  sampleRate *float64
+}
+
+func addAccessLogFlags(c *Command) *accessLogFlags {
+  return &accessLogFlags{
+    path:       c.Flags.String("access-log", "", "json lines access log file, - for stdout, the access log is off when empty"),
+    maxSize:    c.Flags.Int64("access-log-max-size", 100<<20, "size in bytes after which the access log file is rotated, 0 to never rotate"),
+    maxFiles:   c.Flags.Int("access-log-max-files", 5, "number of rotated access log files kept"),
+    sampleRate: c.Flags.Float64("access-log-sample-rate", 1, "share of the queries written to the access log"),
+  }
+}
+
+func (alf *accessLogFlags) newLogger() (*access_log.Logger, error) {
+  if *alf.path == "" {
+    return nil, nil
+  }
+  if *alf.sampleRate <= 0 || *alf.sampleRate > 1 {
+    return nil, fmt.Errorf("the access log sample rate should be in the (0, 1] range")
+  }
+  logger, err := access_log.NewLogger(*alf.path, *alf.maxSize, *alf.maxFiles, *alf.sampleRate)
+  if err != nil {
+    return nil, fmt.Errorf("cannot open the access log: %v", err)
+  }
+  return logger, nil
+}
+
+func setRankingModel(h *suggest.Handler, rankingModelPath string) error {
+  if rankingModelPath == "" {
+    return nil
+  }
+  scorer, err := suggest.LoadLinearScorer(rankingModelPath)
+  if err != nil {
+    return fmt.Errorf("cannot load the ranking model: %v", err)
+  }
+  h.Scorer = scorer
+  h.ClassPriorities = scorer.ClassPriorities
+  return nil
+}
+
+func (ss *suggestSource) newHandler(equalShapedNormalize bool) (*suggest.Handler, error) {
+  suggestData, manifest, err := ss.load()
+  if err != nil {
+    return nil, err
+  }
+  h := &suggest.Handler{
+    Suggest:              suggestData,
+    Policy:               tools.GetPolicy(),
+    EqualShapedNormalize: equalShapedNormalize || (manifest != nil && manifest.Parameters.EqualShapedNormalize()),
+  }
+  h.PrepareIndex()
+  return h, nil
+}
+
+func NewServeCommand() *Command {
+  c := NewCommand("serve", "serve suggest over http and, optionally, grpc")
+  source := addSuggestSourceFlags(c)
+  equalShapedNormalize := c.Flags.Bool("equal-shaped-normalize", false, "additional normalization for cyrillic symbols")
+  port := c.Flags.String("port", "8080", "daemon port")
+  grpcPort := c.Flags.String("grpc-port", "", "daemon grpc port, grpc is disabled when empty")
+  adminPort := c.Flags.String("admin-port", "8081", "port of the users history and the feedback export endpoints, served with personalization or feedback only, keep it private")
+  freshnessHalfLife := c.Flags.Duration("freshness-half-life", 0, "time after which the weight of an item with created_at halves, 0 disables the decay")
+  nodesBudget := c.Flags.Int("nodes-budget", suggest.DefaultNodesBudget, "max number of trie nodes searched to fill the count after filtering, negative to disable")
+  maxBatchSize := c.Flags.Int("max-batch-size", 100, "max number of queries in a /suggest/batch request, 0 for unlimited")
+  rankingModelPath := c.Flags.String("ranking-model", "", "linear ranking model json, the suggestions are ranked by their weights when empty")
+  personalizationFactor := c.Flags.Float64("personalization-factor", 0, "share of the user history in the ranking of requests with the user parameter, 0 disables personalization")
+  historyUsers := c.Flags.Int("history-users", 100000, "max number of users which history is kept")
+  historySelections := c.Flags.Int("history-selections", 50, "number of recent selections kept per user")
+  historySnapshotPath := c.Flags.String("history-snapshot", "", "file to load the users history from and to save it to periodically and on exit")
+  historySnapshotInterval := c.Flags.Duration("history-snapshot-interval", time.Minute, "how often to save the users history snapshot")
+  feedback := c.Flags.Bool("feedback", false, "accept selection feedback on /feedback and boost the weights by it")
+  feedbackHalfLife := c.Flags.Duration("feedback-half-life", 24*time.Hour, "time after which the feedback events count half")
+  feedbackItemFactor := c.Flags.Float64("feedback-item-factor", 0.2, "max relative weight change by the item popularity")
+  feedbackPrefixFactor := c.Flags.Float64("feedback-prefix-factor", 0.5, "max relative weight change by the item popularity for the prefix")
+  feedbackMaxPrefixItems := c.Flags.Int("feedback-max-prefix-items", 1000000, "max number of the prefix and item pairs the feedback is tracked for, 0 for unlimited")
+  accessLog := addAccessLogFlags(c)
+  daemonFlags := addDaemonFlags(c)
+
+  c.Run = func(_ []string) error {
+    h, err := source.newHandler(*equalShapedNormalize)
+    if err != nil {
+      return err
+    }
+    h.FreshnessHalfLife = *freshnessHalfLife
+    h.MaxBatchSize = *maxBatchSize
+    h.NodesBudget = *nodesBudget
+    if err := setRankingModel(h, *rankingModelPath); err != nil {
+      return err
+    }
+    if h.AccessLog, err = accessLog.newLogger(); err != nil {
+      return err
+    }
+    defer h.AccessLog.Close()
+    admin := http.NewServeMux()
+    if *feedback {
+      if *feedbackHalfLife <= 0 {
+        return fmt.Errorf("the feedback half-life should be positive")
+      }
+      aggregator := suggest_feedback.NewAggregator(*feedbackHalfLife, *feedbackItemFactor, *feedbackPrefixFactor)
+      aggregator.MaxPrefixItems = *feedbackMaxPrefixItems
+      h.Booster = aggregator
+      RunServingFeedback(h, aggregator, admin)
+      go aggregator.RunPruning(*feedbackHalfLife, 0.01)
+    }
+    var store *suggest_personalization.LRUHistoryStore
+    if *personalizationFactor > 0 {
+      if *personalizationFactor > 1 {
+        return fmt.Errorf("the personalization factor should be in the [0, 1] range")
+      }
+      if store, err = suggest_personalization.LoadLRUHistoryStore(*historySnapshotPath, *historyUsers, *historySelections); err != nil {
+        return fmt.Errorf("cannot load the history snapshot: %v", err)
+      }
+      h.Reranker = &suggest_personalization.Reranker{Store: store, Factor: *personalizationFactor}
+      RunServingHistory(h, store, admin)
+      if *historySnapshotPath != "" {
+        go store.RunSnapshots(*historySnapshotPath, *historySnapshotInterval)
+      }
+    }
+    daemon := daemonFlags.newDaemon(*port)
+    if store != nil || *feedback {
+      daemon.AdminServer = daemonFlags.newAdminServer(*adminPort)
+      daemon.AdminServer.Handler = admin
+    }
+    RunServingSuggest(h, daemon, *grpcPort)
+    daemon.Shutdown(waitForExitSignal())
+    if store != nil && *historySnapshotPath != "" {
+      if err := store.Snapshot(*historySnapshotPath); err != nil {
+        return fmt.Errorf("cannot write the history snapshot: %v", err)
+      }
+    }
+    return nil
+  }
+  return c
+}
+
+func RunServingSuggest(h *suggest.Handler, daemon *Daemon, grpcPort string) {
+  log.Println("ready to serve")
+  h.SetIndexMetrics()
+
+  http.Handle("/suggest", http.HandlerFunc(h.HandleSuggestRequest))
+  http.Handle("/suggest/batch", http.HandlerFunc(h.HandleBatchSuggestRequest))
+  http.Handle("/metrics", http.HandlerFunc(metrics.Default.HandleMetricsRequest))
+  http.Handle("/health", http.HandlerFunc(h.HandleHealthRequest))
+  http.Handle("/health/live", http.HandlerFunc(daemon.HandleLiveRequest))
+  http.Handle("/health/ready", http.HandlerFunc(daemon.HandleReadyRequest))
+
+  daemon.Component = h
+  if grpcPort != "" {
+    daemon.GrpcServer = grpc.NewServer()
+    daemon.GrpcPort = grpcPort
+    grpcServer := suggest_grpc.NewServer(h)
+    grpcServer.Ready = func() bool {
+      ready, _ := daemon.Ready()
+      return ready
+    }
+    grpcServer.Register(daemon.GrpcServer)
+  }
+  daemon.Serve()
+}
+
+// RunServingHistory serves the users history on the admin mux, as it can be read and changed for any user.
+func RunServingHistory(h *suggest.Handler, store suggest_personalization.HistoryStore, admin *http.ServeMux) {
+  hh := &suggest_personalization.HistoryHandler{Store: store, Handler: h}
+  admin.Handle("/history", http.HandlerFunc(hh.HandleHistoryRequest))
+  admin.Handle("/history/select", http.HandlerFunc(hh.HandleSelectRequest))
+}
+
+// RunServingFeedback accepts the feedback on the public port and serves the export, which has the stored-only data,
+// on the admin mux.
+func RunServingFeedback(h *suggest.Handler, aggregator *suggest_feedback.Aggregator, admin *http.ServeMux) {
+  fh := &suggest_feedback.FeedbackHandler{Aggregator: aggregator, Handler: h}
+  http.Handle("/feedback", http.HandlerFunc(fh.HandleFeedbackRequest))
+  admin.Handle("/feedback/export", withoutWriteTimeout(fh.HandleExportRequest))
+}
@@ -0,0 +1,32 @@
+package suggest
+
+import (
+  "main/access_log"
+  "time"
+)
+
+// LogQuery writes the query to the access log when it is sampled, with the prefix normalized the way the index is searched by.
+// The count is the one the request needs, without the extra suggestion the paginated queries look up.
+func (h *Handler) LogQuery(endpoint, clientId string, q *SuggestQuery, pagingParameters *PagingParameters, suggestionsCount int, start time.Time) {
+  if !h.AccessLog.Sample() {
+    return
+  }
+  _, normalizedPart := h.NormalizePart(q.Part)
+  entry := &access_log.Entry{
+    Time:           start.UTC().Format(time.RFC3339Nano),
+    Endpoint:       endpoint,
+    Index:          h.Name,
+    Prefix:         normalizedPart,
+    Classes:        q.Classes,
+    ExcludeClasses: q.ExcludeClasses,
+    Count:          pagingParameters.NeededCount(),
+    ResultsCount:   suggestionsCount,
+    LatencyMs:      float64(time.Since(start).Microseconds()) / 1000,
+    Version:        h.Suggest.Version,
+    ClientId:       clientId,
+  }
+  for _, filter := range q.Filters {
+    entry.Filters = append(entry.Filters, filter.String())
+  }
+  h.AccessLog.Write(entry)
+}
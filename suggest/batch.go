@@ -0,0 +1,121 @@
+package suggest
+
+import (
+  "encoding/json"
+  "fmt"
+  "main/access_log"
+  "main/network"
+  "net/http"
+  "time"
+)
+
+const maxBatchRequestBytes = 16 << 20
+
+type BatchQuery struct {
+  Part           string   `json:"part"`
+  Classes        []string `json:"classes"`
+  ExcludeClasses []string `json:"exclude_classes"`
+  Filters        []string `json:"filters"`
+  Count          *int     `json:"count"`
+  Page           *int     `json:"page"`
+  Cursor         string   `json:"cursor"`
+  Fields         []string `json:"fields"`
+  User           string   `json:"user"`
+  Deep           bool     `json:"deep"`
+}
+
+func (h *Handler) batchPagingParameters(q *BatchQuery) (*PagingParameters, error) {
+  pagingParameters := &PagingParameters{Count: h.DefaultCount}
+  if q.Count != nil {
+    if *q.Count < 0 {
+      return nil, fmt.Errorf("negative count %d", *q.Count)
+    }
+    pagingParameters.Count = *q.Count
+  }
+  if q.Page != nil {
+    if *q.Page < 0 {
+      return nil, fmt.Errorf("negative page %d", *q.Page)
+    }
+    pagingParameters.Page = *q.Page
+    pagingParameters.PaginationOn = true
+  }
+  return pagingParameters, nil
+}
+
+func (h *Handler) batchCursor(q *BatchQuery, pagingParameters *PagingParameters) error {
+  if q.Cursor == "" {
+    return nil
+  }
+  cursor, err := ParseCursor(q.Cursor)
+  if err != nil {
+    return err
+  }
+  pagingParameters.Cursor = cursor
+  pagingParameters.PaginationOn = true
+  return nil
+}
+
+// HandleBatchSuggestRequest answers a POSTed json array of queries with an array of responses in the same
+// order, each of them shaped the way /suggest shapes it for the api-version of the request.
+func (h *Handler) HandleBatchSuggestRequest(w http.ResponseWriter, r *http.Request) {
+  if r.Method != http.MethodPost {
+    network.WriteCORSHeaders(w)
+    http.Error(w, "batch suggest expects a POST request", http.StatusMethodNotAllowed)
+    return
+  }
+  var queries []*BatchQuery
+  if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchRequestBytes)).Decode(&queries); err != nil {
+    h.reportBadRequest(w, "batch", fmt.Sprintf("cannot parse the batch: %v", err))
+    return
+  }
+  if h.MaxBatchSize > 0 && len(queries) > h.MaxBatchSize {
+    h.reportBadRequest(w, "batch", fmt.Sprintf("%d queries in the batch, at most %d are allowed", len(queries), h.MaxBatchSize))
+    return
+  }
+  apiVersionParameters := NewApiVersionParameters(r.URL.Query())
+  clientId := access_log.ClientId(r)
+  responses := make([]interface{}, 0, len(queries))
+  for idx, q := range queries {
+    start := time.Now()
+    if q == nil {
+      h.reportBadRequest(w, "batch", fmt.Sprintf("query #%d is null", idx))
+      return
+    }
+    filters, err := ParseFilters(q.Filters)
+    if err != nil {
+      h.reportBadRequest(w, "batch", fmt.Sprintf("query #%d: %v", idx, err))
+      return
+    }
+    projection, err := ParseProjection(q.Fields)
+    if err != nil {
+      h.reportBadRequest(w, "batch", fmt.Sprintf("query #%d: %v", idx, err))
+      return
+    }
+    pagingParameters, err := h.batchPagingParameters(q)
+    if err != nil {
+      h.reportBadRequest(w, "batch", fmt.Sprintf("query #%d: %v", idx, err))
+      return
+    }
+    if err := h.batchCursor(q, pagingParameters); err != nil {
+      h.reportBadRequest(w, "batch", fmt.Sprintf("query #%d: %v", idx, err))
+      return
+    }
+    suggestQuery := &SuggestQuery{
+      Part:           q.Part,
+      Classes:        q.Classes,
+      ExcludeClasses: q.ExcludeClasses,
+      Filters:        filters,
+      User:           q.User,
+      Deep:           q.Deep,
+    }
+    h.SetPaging(suggestQuery, pagingParameters)
+    suggestions := h.GetSuggest(suggestQuery)
+    h.ObserveQuery("batch", suggestQuery, len(suggestions), start)
+    h.LogQuery("batch", clientId, suggestQuery, pagingParameters, len(suggestions), start)
+    responses = append(responses, generateResponse(suggestions, pagingParameters, apiVersionParameters, projection))
+  }
+
+  writeSuggestVersionHeader(w, h.Suggest.Version)
+  writeApiVersionHeader(w, apiVersionParameters.Version)
+  network.ReportSuccessData(w, responses)
+}
@@ -0,0 +1,61 @@
+package suggest
+
+import (
+  "encoding/base64"
+  "fmt"
+  "math"
+  "sort"
+  "strconv"
+  "strings"
+)
+
+// Cursor points right after an item in the pagination order: by weight descending, then by id. Unlike
+// page numbers, it keeps the continuation stable when items are added or removed before it, and the merger
+// can pass it to every shard as is.
+type Cursor struct {
+  Weight float32
+  Id     string
+}
+
+func NewCursor(item *SuggestAnswerItem) *Cursor {
+  return &Cursor{Weight: item.Weight, Id: item.Id()}
+}
+
+func (c *Cursor) String() string {
+  return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%08x\t%s", math.Float32bits(c.Weight), c.Id)))
+}
+
+func ParseCursor(s string) (*Cursor, error) {
+  b, err := base64.RawURLEncoding.DecodeString(s)
+  if err != nil {
+    return nil, fmt.Errorf("invalid cursor %q", s)
+  }
+  parts := strings.SplitN(string(b), "\t", 2)
+  if len(parts) != 2 {
+    return nil, fmt.Errorf("invalid cursor %q", s)
+  }
+  bits, err := strconv.ParseUint(parts[0], 16, 32)
+  if err != nil {
+    return nil, fmt.Errorf("invalid cursor %q", s)
+  }
+  return &Cursor{Weight: math.Float32frombits(uint32(bits)), Id: parts[1]}, nil
+}
+
+// Before tells whether the item precedes the cursor position, i.e. was returned before it.
+func (c *Cursor) Before(item *SuggestAnswerItem) bool {
+  return c.precedes(item.Weight, item.Id())
+}
+
+func (c *Cursor) precedes(weight float32, id string) bool {
+  return weight > c.Weight || (weight == c.Weight && id <= c.Id)
+}
+
+// SortForPagination orders the suggestions by weight, breaking the ties by id so that the pages do not overlap.
+func SortForPagination(suggestions []*SuggestAnswerItem) {
+  sort.SliceStable(suggestions, func(i, j int) bool {
+    if suggestions[i].Weight != suggestions[j].Weight {
+      return suggestions[i].Weight > suggestions[j].Weight
+    }
+    return suggestions[i].Id() < suggestions[j].Id()
+  })
+}
@@ -0,0 +1,207 @@
+package suggest
+
+import (
+  stpb "main/proto/suggest/suggest_trie"
+  "sort"
+  "unicode/utf8"
+)
+
+type ItemChange struct {
+  Id        string  `json:"id"`
+  Text      string  `json:"text"`
+  OldWeight float32 `json:"old_weight"`
+  NewWeight float32 `json:"new_weight"`
+}
+
+type PrefixChange struct {
+  Prefix string   `json:"prefix"`
+  Before []string `json:"before"`
+  After  []string `json:"after"`
+}
+
+type ClassChange struct {
+  Class  string `json:"class"`
+  Before int    `json:"before"`
+  After  int    `json:"after"`
+}
+
+type DiffOptions struct {
+  MaxPrefixLength int
+  TopCount        int
+}
+
+type DiffReport struct {
+  OldStats        *IndexStats     `json:"old_stats"`
+  NewStats        *IndexStats     `json:"new_stats"`
+  Added           []*ItemChange   `json:"added"`
+  Removed         []*ItemChange   `json:"removed"`
+  Reweighted      []*ItemChange   `json:"reweighted"`
+  ChangedPrefixes []*PrefixChange `json:"changed_prefixes"`
+  Classes         []*ClassChange  `json:"classes"`
+}
+
+func itemsById(suggestData *stpb.SuggestData) map[string]*stpb.Item {
+  items := map[string]*stpb.Item{}
+  for _, item := range suggestData.Items {
+    if _, ok := items[ItemId(item)]; !ok {
+      items[ItemId(item)] = item
+    }
+  }
+  return items
+}
+
+func Diff(oldData, newData *stpb.SuggestData, options *DiffOptions) *DiffReport {
+  report := &DiffReport{
+    OldStats: CollectStats(oldData),
+    NewStats: CollectStats(newData),
+  }
+
+  oldItems := itemsById(oldData)
+  newItems := itemsById(newData)
+  for id, oldItem := range oldItems {
+    newItem, ok := newItems[id]
+    if !ok {
+      report.Removed = append(report.Removed, &ItemChange{Id: id, Text: oldItem.OriginalText, OldWeight: oldItem.Weight})
+      continue
+    }
+    if newItem.Weight != oldItem.Weight {
+      report.Reweighted = append(report.Reweighted, &ItemChange{Id: id, Text: newItem.OriginalText, OldWeight: oldItem.Weight, NewWeight: newItem.Weight})
+    }
+  }
+  for id, newItem := range newItems {
+    if _, ok := oldItems[id]; !ok {
+      report.Added = append(report.Added, &ItemChange{Id: id, Text: newItem.OriginalText, NewWeight: newItem.Weight})
+    }
+  }
+  sortItemChanges(report.Added, func(c *ItemChange) float32 { return c.NewWeight })
+  sortItemChanges(report.Removed, func(c *ItemChange) float32 { return c.OldWeight })
+  sortItemChanges(report.Reweighted, func(c *ItemChange) float32 { return abs(c.NewWeight - c.OldWeight) })
+
+  diffPrefixes(oldData, newData, oldData.Trie, newData.Trie, nil, options, report)
+
+  classes := map[string]bool{}
+  for class := range report.OldStats.ClassesCount {
+    classes[class] = true
+  }
+  for class := range report.NewStats.ClassesCount {
+    classes[class] = true
+  }
+  for class := range classes {
+    before, after := report.OldStats.ClassesCount[class], report.NewStats.ClassesCount[class]
+    if before != after {
+      report.Classes = append(report.Classes, &ClassChange{Class: class, Before: before, After: after})
+    }
+  }
+  sort.Slice(report.Classes, func(i, j int) bool {
+    return report.Classes[i].Class < report.Classes[j].Class
+  })
+  return report
+}
+
+func abs(x float32) float32 {
+  if x < 0 {
+    return -x
+  }
+  return x
+}
+
+func sortItemChanges(changes []*ItemChange, key func(c *ItemChange) float32) {
+  sort.Slice(changes, func(i, j int) bool {
+    if key(changes[i]) != key(changes[j]) {
+      return key(changes[i]) > key(changes[j])
+    }
+    return changes[i].Id < changes[j].Id
+  })
+}
+
+func topIds(suggestData *stpb.SuggestData, prefix []byte, count int) []string {
+  items := GetSuggestItems(suggestData, prefix, nil)
+  if len(items) > count {
+    items = items[:count]
+  }
+  ids := make([]string, 0, len(items))
+  for _, item := range items {
+    ids = append(ids, ItemId(item.Item))
+  }
+  return ids
+}
+
+func sameIds(a, b []string) bool {
+  if len(a) != len(b) {
+    return false
+  }
+  for i := range a {
+    if a[i] != b[i] {
+      return false
+    }
+  }
+  return true
+}
+
+func descendantTrie(trie *stpb.SuggestTrie, key uint32) *stpb.SuggestTrie {
+  if trie == nil {
+    return nil
+  }
+  for idx, k := range trie.DescendantKeys {
+    if k == key {
+      return trie.DescendantTries[idx]
+    }
+  }
+  return nil
+}
+
+// endsWithFullRune tells whether the prefix does not end inside a multibyte character.
+func endsWithFullRune(prefix []byte) bool {
+  start := len(prefix) - 1
+  for start > 0 && !utf8.RuneStart(prefix[start]) {
+    start--
+  }
+  return start < 0 || utf8.FullRune(prefix[start:])
+}
+
+// diffPrefixes walks both tries together, in key order, comparing the top items of every prefix up to the max length
+// in characters. The trie keys are bytes, so the prefixes ending inside a multibyte character are only walked through.
+func diffPrefixes(
+  oldData, newData *stpb.SuggestData,
+  oldTrie, newTrie *stpb.SuggestTrie,
+  prefix []byte,
+  options *DiffOptions,
+  report *DiffReport,
+) {
+  fullRune := endsWithFullRune(prefix)
+  if len(prefix) > 0 && fullRune {
+    before := topIds(oldData, prefix, options.TopCount)
+    after := topIds(newData, prefix, options.TopCount)
+    if !sameIds(before, after) {
+      report.ChangedPrefixes = append(report.ChangedPrefixes, &PrefixChange{
+        Prefix: string(prefix),
+        Before: before,
+        After:  after,
+      })
+    }
+  }
+  if fullRune && utf8.RuneCount(prefix) >= options.MaxPrefixLength {
+    return
+  }
+
+  keysMap := map[uint32]bool{}
+  for _, trie := range []*stpb.SuggestTrie{oldTrie, newTrie} {
+    if trie == nil {
+      continue
+    }
+    for _, key := range trie.DescendantKeys {
+      keysMap[key] = true
+    }
+  }
+  keys := make([]uint32, 0, len(keysMap))
+  for key := range keysMap {
+    keys = append(keys, key)
+  }
+  sort.Slice(keys, func(i, j int) bool {
+    return keys[i] < keys[j]
+  })
+  for _, key := range keys {
+    descendantPrefix := append(append([]byte{}, prefix...), byte(key))
+    diffPrefixes(oldData, newData, descendantTrie(oldTrie, key), descendantTrie(newTrie, key), descendantPrefix, options, report)
+  }
+}
@@ -0,0 +1,157 @@
+package suggest
+
+import (
+  "fmt"
+  "google.golang.org/protobuf/types/known/structpb"
+  stpb "main/proto/suggest/suggest_trie"
+  "math"
+  "sort"
+  "strconv"
+  "strings"
+)
+
+// Filter restricts the suggestions by a data field. It is written as field:value for equality,
+// field:value1|value2 for set membership and field:[min..max] for numeric ranges, either bound of which
+// may be omitted.
+type Filter struct {
+  Field  string
+  Values map[string]bool
+  Min    float64
+  Max    float64
+  Range  bool
+}
+
+func ParseFilter(s string) (*Filter, error) {
+  parts := strings.SplitN(s, ":", 2)
+  if len(parts) != 2 || parts[0] == "" {
+    return nil, fmt.Errorf("filter %q should look like field:value", s)
+  }
+  filter := &Filter{Field: parts[0]}
+  spec := parts[1]
+  if strings.HasPrefix(spec, "[") && strings.HasSuffix(spec, "]") && strings.Contains(spec, "..") {
+    bounds := strings.SplitN(spec[1:len(spec)-1], "..", 2)
+    filter.Range = true
+    filter.Min, filter.Max = math.Inf(-1), math.Inf(1)
+    for idx, bound := range []*float64{&filter.Min, &filter.Max} {
+      if bounds[idx] == "" {
+        continue
+      }
+      value, err := strconv.ParseFloat(bounds[idx], 64)
+      if err != nil {
+        return nil, fmt.Errorf("filter %q: cannot interpret %q as number", s, bounds[idx])
+      }
+      *bound = value
+    }
+    return filter, nil
+  }
+  filter.Values = map[string]bool{}
+  for _, value := range strings.Split(spec, "|") {
+    filter.Values[value] = true
+  }
+  return filter, nil
+}
+
+// String writes the filter back in the canonical form, with the set values sorted and the open range bounds left out.
+func (f *Filter) String() string {
+  if f.Range {
+    bounds := make([]string, 2)
+    for idx, bound := range []float64{f.Min, f.Max} {
+      if !math.IsInf(bound, 0) {
+        bounds[idx] = strconv.FormatFloat(bound, 'g', -1, 64)
+      }
+    }
+    return fmt.Sprintf("%s:[%s..%s]", f.Field, bounds[0], bounds[1])
+  }
+  values := make([]string, 0, len(f.Values))
+  for value := range f.Values {
+    values = append(values, value)
+  }
+  sort.Strings(values)
+  return f.Field + ":" + strings.Join(values, "|")
+}
+
+func ParseFilters(values []string) ([]*Filter, error) {
+  var filters []*Filter
+  for _, value := range values {
+    filter, err := ParseFilter(value)
+    if err != nil {
+      return nil, err
+    }
+    filters = append(filters, filter)
+  }
+  return filters, nil
+}
+
+func numericValue(value *structpb.Value) (float64, bool) {
+  switch v := value.Kind.(type) {
+  case *structpb.Value_NumberValue:
+    return v.NumberValue, true
+  case *structpb.Value_StringValue:
+    if number, err := strconv.ParseFloat(v.StringValue, 64); err == nil {
+      return number, true
+    }
+  }
+  return 0, false
+}
+
+// itemValue returns the string and the numeric forms of the item field, preferring the facets indexed at build time.
+func itemValue(item *stpb.Item, field string, indexed bool) (string, float64, bool, bool) {
+  if indexed {
+    s, ok := item.Facets[field]
+    number, numberOk := item.NumericFacets[field]
+    return s, number, ok, numberOk
+  }
+  value, ok := item.GetData().GetFields()[field]
+  if !ok {
+    if value, ok = item.GetStoredData().GetFields()[field]; !ok {
+      return "", 0, false, false
+    }
+  }
+  number, numberOk := numericValue(value)
+  return DataValueString(value.AsInterface()), number, true, numberOk
+}
+
+func (f *Filter) match(item *stpb.Item, indexed bool) bool {
+  s, number, ok, numberOk := itemValue(item, f.Field, indexed)
+  if f.Range {
+    return numberOk && number >= f.Min && number <= f.Max
+  }
+  return ok && f.Values[s]
+}
+
+// matchFilters checks the item against all the filters, the facets of the index are used instead of the item data.
+func matchFilters(item *stpb.Item, filters []*Filter, facets map[string]bool) bool {
+  for _, filter := range filters {
+    if !filter.match(item, facets[filter.Field]) {
+      return false
+    }
+  }
+  return true
+}
+
+// SetFacets indexes the data fields for filtering, keeping their canonical string and numeric forms on every item.
+// It should go before SplitStoredData, stored-only fields are indexed the same way.
+func SetFacets(suggestData *stpb.SuggestData, facets []string) {
+  suggestData.Facets = facets
+  for _, item := range suggestData.Items {
+    if item.Data == nil {
+      continue
+    }
+    for _, facet := range facets {
+      value, ok := item.Data.Fields[facet]
+      if !ok {
+        continue
+      }
+      if item.Facets == nil {
+        item.Facets = map[string]string{}
+      }
+      item.Facets[facet] = DataValueString(value.AsInterface())
+      if number, ok := numericValue(value); ok {
+        if item.NumericFacets == nil {
+          item.NumericFacets = map[string]float64{}
+        }
+        item.NumericFacets[facet] = number
+      }
+    }
+  }
+}
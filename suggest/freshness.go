@@ -0,0 +1,83 @@
+package suggest
+
+import (
+  stpb "main/proto/suggest/suggest_trie"
+  "math"
+  "sort"
+  "time"
+)
+
+const (
+  CreatedAtField = "created_at"
+  ExpiresAtField = "expires_at"
+)
+
+// dataTime reads a time field of the item data, either an RFC 3339 string or unix seconds. The zero time
+// is returned for missing or malformed fields, so such items never fade or expire.
+func dataTime(data map[string]interface{}, field string) time.Time {
+  return parseDataTime(data[field])
+}
+
+func parseDataTime(value interface{}) time.Time {
+  switch v := value.(type) {
+  case string:
+    if t, err := time.Parse(time.RFC3339, v); err == nil {
+      return t
+    }
+  case float64:
+    return time.Unix(int64(v), 0)
+  }
+  return time.Time{}
+}
+
+func isExpired(data map[string]interface{}, now time.Time) bool {
+  expiresAt := dataTime(data, ExpiresAtField)
+  return !expiresAt.IsZero() && !now.Before(expiresAt)
+}
+
+func itemExpired(item *stpb.Item, now time.Time) bool {
+  if item.Data == nil {
+    return false
+  }
+  value, ok := item.Data.Fields[ExpiresAtField]
+  if !ok {
+    return false
+  }
+  expiresAt := parseDataTime(value.AsInterface())
+  return !expiresAt.IsZero() && !now.Before(expiresAt)
+}
+
+func (item *Item) Expired(now time.Time) bool {
+  return isExpired(item.Data, now)
+}
+
+// FreshnessFactor halves the weight of an item every halfLife since its creation.
+func FreshnessFactor(data map[string]interface{}, now time.Time, halfLife time.Duration) float64 {
+  createdAt := dataTime(data, CreatedAtField)
+  if halfLife <= 0 || createdAt.IsZero() || !now.After(createdAt) {
+    return 1
+  }
+  return math.Exp2(-now.Sub(createdAt).Seconds() / halfLife.Seconds())
+}
+
+// ApplyFreshness drops the expired suggestions and decays the weights of the rest, keeping the order by weight.
+func ApplyFreshness(suggestions []*SuggestAnswerItem, now time.Time, halfLife time.Duration) []*SuggestAnswerItem {
+  fresh := suggestions[:0]
+  decayed := false
+  for _, item := range suggestions {
+    if isExpired(item.Data, now) {
+      continue
+    }
+    if factor := FreshnessFactor(item.Data, now, halfLife); factor != 1 {
+      item.Weight = float32(float64(item.Weight) * factor)
+      decayed = true
+    }
+    fresh = append(fresh, item)
+  }
+  if decayed {
+    sort.SliceStable(fresh, func(i, j int) bool {
+      return fresh[i].Weight > fresh[j].Weight
+    })
+  }
+  return fresh
+}
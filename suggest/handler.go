@@ -1,24 +1,82 @@
 package suggest
 
 import (
+  "fmt"
   "github.com/microcosm-cc/bluemonday"
+  "main/access_log"
   "main/network"
   stpb "main/proto/suggest/suggest_trie"
   "main/tools"
   "math"
   "net/http"
   "net/url"
+  "sort"
   "strconv"
+  "time"
 )
 
+// Reranker reorders the suggestions retrieved for a query from a known user.
+type Reranker interface {
+  Rerank(user string, suggestions []*SuggestAnswerItem) []*SuggestAnswerItem
+}
+
+// Booster adjusts the weights of the suggestions retrieved for the normalized query part.
+type Booster interface {
+  Boost(normalizedPart string, suggestions []*SuggestAnswerItem) []*SuggestAnswerItem
+}
+
 type Handler struct {
+  Name                 string
   Suggest              *stpb.SuggestData
   Policy               *bluemonday.Policy
   EqualShapedNormalize bool
+  FreshnessHalfLife    time.Duration
+  DefaultCount         int
+  NodesBudget          int
+  MaxBatchSize         int
+  Scorer               Scorer
+  ClassPriorities      map[string]float64
+  Booster              Booster
+  Reranker             Reranker
+  AccessLog            *access_log.Logger
+
+  itemsByText map[string]uint32
+  classes     map[string]bool
 }
 
 func (h *Handler) HandleHealthRequest(w http.ResponseWriter, _ *http.Request) {
-  network.ReportSuccessMessage(w, "OK")
+  ready, _ := h.Health()
+  network.ReportHealth(w, ready)
+}
+
+// IndexHealth describes the served index. An index is validated when it is loaded, so a loaded one is a valid one.
+type IndexHealth struct {
+  Name       string   `json:"name,omitempty"`
+  Loaded     bool     `json:"loaded"`
+  Validated  bool     `json:"validated"`
+  Version    uint64   `json:"version"`
+  ItemsCount int      `json:"items_count"`
+  Classes    []string `json:"classes,omitempty"`
+}
+
+func (h *Handler) IndexHealth() *IndexHealth {
+  if h.Suggest == nil || h.Suggest.Trie == nil {
+    return &IndexHealth{Name: h.Name}
+  }
+  return &IndexHealth{
+    Name:       h.Name,
+    Loaded:     true,
+    Validated:  true,
+    Version:    h.Suggest.Version,
+    ItemsCount: len(h.Suggest.Items),
+    Classes:    h.Classes(),
+  }
+}
+
+// Health reports the handler ready once its index is loaded.
+func (h *Handler) Health() (bool, interface{}) {
+  health := h.IndexHealth()
+  return health.Loaded, health
 }
 
 type ApiVersionParameters struct {
@@ -37,66 +95,134 @@ type PagingParameters struct {
   Count        int
   Page         int
   PaginationOn bool
+  Cursor       *Cursor
+  // StoredItemsCount is the number of the prefix matches counted at build time, the totals use it when the lookup
+  // did not collect all of them.
+  StoredItemsCount int
+  // Deep tells that the suggestions come from the deep walk, ranked by the stored weights. Only then the next cursor
+  // is returned, since the cursors continue the deep walk.
+  Deep bool
 }
 
+// NewPagingParameters takes the paging from the query, ignoring the invalid and the negative count and page.
 func NewPagingParameters(query url.Values) *PagingParameters {
   pagingParameters := &PagingParameters{}
-  if count, err := strconv.ParseInt(query.Get("count"), 10, 64); err == nil { // no err
+  if count, err := strconv.ParseInt(query.Get("count"), 10, 64); err == nil && count >= 0 {
     pagingParameters.Count = int(count)
   }
-  if page, err := strconv.ParseInt(query.Get("page"), 10, 64); err == nil { // no err
+  if page, err := strconv.ParseInt(query.Get("page"), 10, 64); err == nil && page >= 0 {
     pagingParameters.Page = int(page)
     pagingParameters.PaginationOn = true
   }
+  if cursor, err := ParseCursor(query.Get("cursor")); err == nil { // no err
+    pagingParameters.Cursor = cursor
+    pagingParameters.PaginationOn = true
+  }
+  return pagingParameters
+}
+
+// newPagingParameters applies the handler default count when the query does not set one.
+func (h *Handler) newPagingParameters(query url.Values) *PagingParameters {
+  pagingParameters := NewPagingParameters(query)
+  if query.Get("count") == "" {
+    pagingParameters.Count = h.DefaultCount
+  }
   return pagingParameters
 }
 
+// Apply cuts the page starting right after the cursor when there is one and the page with the requested
+// number otherwise. The next cursor is set while there are more items and the walk is deep.
 func (pp *PagingParameters) Apply(suggestions []*SuggestAnswerItem) *PaginatedSuggestResponse {
+  SortForPagination(suggestions)
+  itemsCount := len(suggestions)
+  totalItemsCount := itemsCount
+  if pp.StoredItemsCount > totalItemsCount {
+    totalItemsCount = pp.StoredItemsCount
+  }
   pagesCount := 1
   if pp.Count != 0 {
-    pagesCount = int(math.Ceil(float64(len(suggestions)) / float64(pp.Count)))
+    pagesCount = int(math.Ceil(float64(totalItemsCount) / float64(pp.Count)))
   }
-  itemsCount := len(suggestions)
-  if pp.Page != 0 && pp.Count != 0 {
-    skip := pp.Page * pp.Count
-    if len(suggestions) > skip {
-      suggestions = suggestions[skip:]
-    } else {
-      suggestions = nil
+  pageNumber := pp.Page
+  skip := 0
+  if pp.Cursor != nil {
+    for skip < len(suggestions) && pp.Cursor.Before(suggestions[skip]) {
+      skip++
+    }
+    if pp.Count != 0 {
+      pageNumber = skip / pp.Count
     }
+  } else if pp.Page != 0 && pp.Count != 0 {
+    skip = pp.Page * pp.Count
+  }
+  if len(suggestions) > skip {
+    suggestions = suggestions[skip:]
+  } else {
+    suggestions = nil
   }
   if pp.Count != 0 && len(suggestions) > pp.Count {
     suggestions = suggestions[:pp.Count]
   }
-  return &PaginatedSuggestResponse{
+  response := &PaginatedSuggestResponse{
     Suggestions:     suggestions,
-    PageNumber:      pp.Page,
+    PageNumber:      pageNumber,
     TotalPagesCount: pagesCount,
-    TotalItemsCount: itemsCount,
+    TotalItemsCount: totalItemsCount,
+  }
+  if len(suggestions) > 0 && skip+len(suggestions) < itemsCount && pp.Deep {
+    response.NextCursor = NewCursor(suggestions[len(suggestions)-1]).String()
+  }
+  return response
+}
+
+func truncateSuggestions(suggestions []*SuggestAnswerItem, count int) []*SuggestAnswerItem {
+  if count != 0 && len(suggestions) > count {
+    return suggestions[:count]
+  }
+  return suggestions
+}
+
+// NeededCount is the number of suggestions the request needs to be answered, 0 for all of them.
+func (pp *PagingParameters) NeededCount() int {
+  if pp.PaginationOn {
+    return (pp.Page + 1) * pp.Count
+  }
+  return pp.Count
+}
+
+// Paginate returns the requested page when pagination is on and the first Count suggestions otherwise.
+func (pp *PagingParameters) Paginate(suggestions []*SuggestAnswerItem) *PaginatedSuggestResponse {
+  if pp.PaginationOn {
+    return pp.Apply(suggestions)
   }
+  return &PaginatedSuggestResponse{Suggestions: truncateSuggestions(suggestions, pp.Count)}
 }
 
 func generateResponse(
   suggestions []*SuggestAnswerItem,
   pagingParameters *PagingParameters,
   apiVersionParameters *ApiVersionParameters,
+  projection *Projection,
 ) interface{} {
 
   if pagingParameters.PaginationOn {
     response := pagingParameters.Apply(suggestions)
+    projection.Apply(response.Suggestions)
     return response
   }
 
-  count := pagingParameters.Count
-  if count != 0 && len(suggestions) > count {
-    suggestions = suggestions[:count]
+  totalItemsCount := len(suggestions)
+  if pagingParameters.StoredItemsCount > totalItemsCount {
+    totalItemsCount = pagingParameters.StoredItemsCount
   }
+  suggestions = truncateSuggestions(suggestions, pagingParameters.Count)
+  projection.Apply(suggestions)
 
   if apiVersionParameters.Version == 1 {
     return suggestions
   }
 
-  return SuggestResponse{Suggestions: suggestions}
+  return SuggestResponse{Suggestions: suggestions, TotalItemsCount: totalItemsCount}
 }
 
 func writeSuggestVersionHeader(w http.ResponseWriter, version uint64) {
@@ -107,9 +233,7 @@ func writeApiVersionHeader(w http.ResponseWriter, version int) {
   w.Header().Add("Api-Version", strconv.Itoa(version))
 }
 
-func (h *Handler) HandleSuggestRequest(w http.ResponseWriter, r *http.Request) {
-  network.WriteCORSHeaders(w)
-  part := r.URL.Query().Get("part")
+func (h *Handler) NormalizePart(part string) (string, string) {
   if h.EqualShapedNormalize {
     part = tools.ToEqualShapedLatin(part)
   }
@@ -119,15 +243,230 @@ func (h *Handler) HandleSuggestRequest(w http.ResponseWriter, r *http.Request) {
   } else {
     normalizedPart = tools.NormalizeString(part, h.Policy)
   }
-  classes := r.URL.Query()["class"]
-  classesMap := tools.PrepareCheckMap(classes)
-  excludeClasses := r.URL.Query()["exclude-class"]
-  excludeClassesMap := tools.PrepareCheckMap(excludeClasses)
-  suggestions := GetSuggest(h.Suggest, part, normalizedPart, classesMap, excludeClassesMap)
-  pagingParameters := NewPagingParameters(r.URL.Query())
+  return part, normalizedPart
+}
+
+type SuggestQuery struct {
+  Part           string
+  Classes        []string
+  ExcludeClasses []string
+  Filters        []*Filter
+  Count          int
+  Deep           bool
+  After          *Cursor
+  Projection     *Projection
+  User           string
+  Debug          bool
+}
+
+func NewSuggestQuery(query url.Values) (*SuggestQuery, error) {
+  filters, err := ParseFilters(query["filter"])
+  if err != nil {
+    return nil, err
+  }
+  if cursor := query.Get("cursor"); cursor != "" {
+    if _, err := ParseCursor(cursor); err != nil {
+      return nil, err
+    }
+  }
+  projection, err := ParseProjection(query["fields"])
+  if err != nil {
+    return nil, err
+  }
+  return &SuggestQuery{
+    Part:           query.Get("part"),
+    Classes:        query["class"],
+    ExcludeClasses: query["exclude-class"],
+    Filters:        filters,
+    Projection:     projection,
+    User:           query.Get("user"),
+    Debug:          query.Get("debug") == "1",
+    Deep:           query.Get("deep") == "1",
+  }, nil
+}
+
+// SetPaging asks for the suggestions the page needs and, for paginated requests, one more to tell whether the page
+// is the last one. The queries continuing from a cursor walk the prefix subtree deep, so that the matches the prefix
+// node does not keep can be reached. The deep walk ranks by the stored weights, which the cursors point into.
+func (q *SuggestQuery) SetPaging(pagingParameters *PagingParameters) {
+  q.Count = pagingParameters.NeededCount()
+  if pagingParameters.PaginationOn && q.Count != 0 {
+    q.Count++
+  }
+  q.After = pagingParameters.Cursor
+  q.Deep = q.Deep || pagingParameters.Cursor != nil
+  pagingParameters.Deep = q.Deep
+}
+
+// SetPaging sets the query paging and the stored count of the prefix matches for the totals. The counts are taken
+// before any filtering, so they are used for the queries without class and data filters only.
+func (h *Handler) SetPaging(q *SuggestQuery, pagingParameters *PagingParameters) {
+  q.SetPaging(pagingParameters)
+  if len(q.Classes) == 0 && len(q.ExcludeClasses) == 0 && len(q.Filters) == 0 {
+    pagingParameters.StoredItemsCount = h.PrefixItemsCount(q.Part)
+  }
+}
+
+// nodesBudget is the number of trie nodes searched to fill the count, the default one when not set and none when negative.
+func (h *Handler) nodesBudget() int {
+  if h.NodesBudget == 0 {
+    return DefaultNodesBudget
+  }
+  return h.NodesBudget
+}
+
+// GetSuggest looks the suggestions up and ranks them. The deep queries keep the stored weights, the expired items
+// are dropped still, so that their order is the one of the deep walk and of its cursors: the freshness decay,
+// the ranking model, the feedback and the personalization apply to the other queries only.
+func (h *Handler) GetSuggest(q *SuggestQuery) []*SuggestAnswerItem {
+  part, normalizedPart := h.NormalizePart(q.Part)
+  now := time.Now()
+  suggestions := GetSuggest(h.Suggest, part, normalizedPart, &ItemsQuery{
+    Classes:        tools.PrepareCheckMap(q.Classes),
+    ExcludeClasses: tools.PrepareCheckMap(q.ExcludeClasses),
+    Filters:        q.Filters,
+    Now:            now,
+    Count:          q.Count,
+    NodesBudget:    h.nodesBudget(),
+    Deep:           q.Deep,
+    After:          q.After,
+  })
+  if q.Deep {
+    suggestions = ApplyFreshness(suggestions, now, 0)
+    if q.Debug {
+      suggestions = h.score(WeightScorer{}, normalizedPart, suggestions, true)
+    }
+    SortForPagination(suggestions)
+    return suggestions
+  }
+  suggestions = ApplyFreshness(suggestions, now, h.FreshnessHalfLife)
+  if h.Scorer != nil || q.Debug {
+    suggestions = h.score(h.Scorer, normalizedPart, suggestions, q.Debug)
+  }
+  if h.Booster != nil {
+    suggestions = h.Booster.Boost(normalizedPart, suggestions)
+  }
+  if h.Reranker != nil && q.User != "" {
+    suggestions = h.Reranker.Rerank(q.User, suggestions)
+  }
+  SortForPagination(suggestions)
+  return suggestions
+}
+
+// score replaces the suggestion weights by the scorer scores, attaching the features in the debug mode.
+func (h *Handler) score(scorer Scorer, normalizedPart string, suggestions []*SuggestAnswerItem, debug bool) []*SuggestAnswerItem {
+  if scorer == nil {
+    scorer = WeightScorer{}
+  }
+  for _, item := range suggestions {
+    normalizedText := item.NormalizedText
+    if normalizedText == "" {
+      _, normalizedText = h.NormalizePart(item.Text())
+    }
+    features := NewFeatures(normalizedPart, normalizedText, item, h.ClassPriorities)
+    item.Weight = float32(scorer.Score(features))
+    if debug {
+      item.Features = features
+    }
+  }
+  sort.SliceStable(suggestions, func(i, j int) bool {
+    return suggestions[i].Weight > suggestions[j].Weight
+  })
+  return suggestions
+}
+
+// GetPaginatedSuggest answers the query the same way HandleSuggestRequest does with api-version=2, but
+// without the HTTP round trip, so that the merger can use the handler as an in-process shard.
+func (h *Handler) GetPaginatedSuggest(query url.Values) (*PaginatedSuggestResponse, error) {
+  q, err := NewSuggestQuery(query)
+  if err != nil {
+    return nil, err
+  }
+  pagingParameters := h.newPagingParameters(query)
+  h.SetPaging(q, pagingParameters)
+  response := pagingParameters.Paginate(h.GetSuggest(q))
+  q.Projection.Apply(response.Suggestions)
+  return response, nil
+}
+
+// PrefixItemsCount is the number of items matching the part stored at build time, before any filtering,
+// 0 for the indexes built without the counts.
+func (h *Handler) PrefixItemsCount(part string) int {
+  _, normalizedPart := h.NormalizePart(part)
+  trie := findPrefixTrie(h.Suggest, []byte(normalizedPart))
+  if trie == nil {
+    return 0
+  }
+  return int(trie.TotalItemsCount)
+}
+
+// PrepareIndex builds the item lookups and collects the classes of the loaded index. The normalized texts of the items are stored at build
+// time, the ones of the indexes built before that and the equal-shaped ones, which the build does not apply,
+// are normalized here.
+func (h *Handler) PrepareIndex() {
+  h.classes = map[string]bool{}
+  for _, item := range h.Suggest.Items {
+    if item.NormalizedText == "" || h.EqualShapedNormalize {
+      _, item.NormalizedText = h.NormalizePart(item.OriginalText)
+    }
+    if class := ItemClass(item); class != "" {
+      h.classes[class] = true
+    }
+  }
+  h.itemsByText = ItemsByText(h.Suggest)
+}
+
+// Classes returns the sorted classes of the loaded index.
+func (h *Handler) Classes() []string {
+  classes := make([]string, 0, len(h.classes))
+  for class := range h.classes {
+    classes = append(classes, class)
+  }
+  sort.Strings(classes)
+  return classes
+}
+
+// GetItem returns an item which text normalizes to the same string as the given one, nil if there is none.
+func (h *Handler) GetItem(text string) *stpb.Item {
+  _, normalizedText := h.NormalizePart(text)
+  if itemIdx, ok := h.itemsByText[normalizedText]; ok {
+    return h.Suggest.Items[itemIdx]
+  }
+  return nil
+}
+
+func (h *Handler) reportProtoResponse(w http.ResponseWriter, suggestions []*SuggestAnswerItem, pagingParameters *PagingParameters, projection *Projection) {
+  paginatedResponse := pagingParameters.Paginate(suggestions)
+  projection.Apply(paginatedResponse.Suggestions)
+  response, err := paginatedResponse.ToProto()
+  if err != nil {
+    network.ReportServerError(w, fmt.Sprintf("%v", err))
+    return
+  }
+  network.ReportSuccessProto(w, ProtoContentType, response)
+}
+
+func (h *Handler) HandleSuggestRequest(w http.ResponseWriter, r *http.Request) {
+  start := time.Now()
+  network.WriteCORSHeaders(w)
+  q, err := NewSuggestQuery(r.URL.Query())
+  if err != nil {
+    h.reportBadRequest(w, "suggest", fmt.Sprintf("%v", err))
+    return
+  }
+  pagingParameters := h.newPagingParameters(r.URL.Query())
+  h.SetPaging(q, pagingParameters)
+  suggestions := h.GetSuggest(q)
+  h.ObserveQuery("suggest", q, len(suggestions), start)
+  h.LogQuery("suggest", access_log.ClientId(r), q, pagingParameters, len(suggestions), start)
   apiVersionParameters := NewApiVersionParameters(r.URL.Query())
 
   writeSuggestVersionHeader(w, h.Suggest.Version)
   writeApiVersionHeader(w, apiVersionParameters.Version)
-  network.ReportSuccessData(w, generateResponse(suggestions, pagingParameters, apiVersionParameters))
+  if AcceptsProto(r.Header) {
+    h.reportProtoResponse(w, suggestions, pagingParameters, q.Projection)
+    return
+  }
+  response := generateResponse(suggestions, pagingParameters, apiVersionParameters, q.Projection)
+  network.ReportSuccessData(w, response)
 }
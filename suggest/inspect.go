@@ -0,0 +1,69 @@
+package suggest
+
+import (
+  stpb "main/proto/suggest/suggest_trie"
+  "math"
+  "strings"
+)
+
+type IndexStats struct {
+  Version      uint64         `json:"version"`
+  ItemsCount   int            `json:"items_count"`
+  NodesCount   int            `json:"nodes_count"`
+  MaxDepth     int            `json:"max_depth"`
+  MinWeight    float32        `json:"min_weight"`
+  MaxWeight    float32        `json:"max_weight"`
+  ClassesCount map[string]int `json:"classes_count"`
+}
+
+// ItemClass returns the class of the item the same way the builder groups items by class.
+func ItemClass(item *stpb.Item) string {
+  if item.Data == nil {
+    return ""
+  }
+  class, ok := item.Data.Fields["class"]
+  if !ok {
+    return ""
+  }
+  return strings.ToLower(class.GetStringValue())
+}
+
+func trieDepth(trie *stpb.SuggestTrie) int {
+  depth := 0
+  for _, descendant := range trie.DescendantTries {
+    if d := trieDepth(descendant) + 1; d > depth {
+      depth = d
+    }
+  }
+  return depth
+}
+
+func CollectStats(suggestData *stpb.SuggestData) *IndexStats {
+  stats := &IndexStats{
+    Version:      suggestData.Version,
+    ItemsCount:   len(suggestData.Items),
+    NodesCount:   CountNodes(suggestData.Trie),
+    MaxDepth:     trieDepth(suggestData.Trie),
+    MinWeight:    math.MaxFloat32,
+    MaxWeight:    -math.MaxFloat32,
+    ClassesCount: map[string]int{},
+  }
+  for _, item := range suggestData.Items {
+    stats.ClassesCount[ItemClass(item)]++
+    if item.Weight < stats.MinWeight {
+      stats.MinWeight = item.Weight
+    }
+    if item.Weight > stats.MaxWeight {
+      stats.MaxWeight = item.Weight
+    }
+  }
+  if stats.ItemsCount == 0 {
+    stats.MinWeight, stats.MaxWeight = 0, 0
+  }
+  return stats
+}
+
+// GetTopItems returns the items stored for the normalized prefix, ordered the way lookups return them.
+func GetTopItems(suggestData *stpb.SuggestData, normalizedPrefix string) []*PrefixItem {
+  return GetSuggestItems(suggestData, []byte(normalizedPrefix), nil)
+}
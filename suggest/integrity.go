@@ -0,0 +1,172 @@
+package suggest
+
+import (
+  "bytes"
+  "encoding/binary"
+  "fmt"
+  "google.golang.org/protobuf/proto"
+  "hash/crc32"
+  "log"
+  stpb "main/proto/suggest/suggest_trie"
+  "math"
+)
+
+// Suggest data files start with a fixed size header: the magic, the format version, the payload length and
+// the CRC-32C of the payload, all little endian. Files written before the header was introduced have format version 0.
+const (
+  SuggestFormatVersion = 1
+  headerSize           = 20
+)
+
+var (
+  suggestMagic = []byte("SGST")
+  crcTable     = crc32.MakeTable(crc32.Castagnoli)
+)
+
+type FileHeader struct {
+  FormatVersion uint32
+  PayloadLength uint64
+  Checksum      uint32
+}
+
+func MarshalSuggest(suggestData *stpb.SuggestData) ([]byte, error) {
+  payload, err := proto.Marshal(suggestData)
+  if err != nil {
+    return nil, err
+  }
+  b := make([]byte, headerSize, headerSize+len(payload))
+  copy(b, suggestMagic)
+  binary.LittleEndian.PutUint32(b[4:8], SuggestFormatVersion)
+  binary.LittleEndian.PutUint64(b[8:16], uint64(len(payload)))
+  binary.LittleEndian.PutUint32(b[16:20], crc32.Checksum(payload, crcTable))
+  return append(b, payload...), nil
+}
+
+// ReadHeader splits the file content into the header and the payload, checking the payload against the header.
+func ReadHeader(b []byte) (*FileHeader, []byte, error) {
+  if !bytes.HasPrefix(b, suggestMagic) {
+    return &FileHeader{PayloadLength: uint64(len(b))}, b, nil
+  }
+  if len(b) < headerSize {
+    return nil, nil, fmt.Errorf("truncated header: %d bytes, %d expected", len(b), headerSize)
+  }
+  header := &FileHeader{
+    FormatVersion: binary.LittleEndian.Uint32(b[4:8]),
+    PayloadLength: binary.LittleEndian.Uint64(b[8:16]),
+    Checksum:      binary.LittleEndian.Uint32(b[16:20]),
+  }
+  if header.FormatVersion > SuggestFormatVersion {
+    return nil, nil, fmt.Errorf("unsupported format version %d, at most %d is supported", header.FormatVersion, SuggestFormatVersion)
+  }
+  payload := b[headerSize:]
+  if uint64(len(payload)) != header.PayloadLength {
+    return nil, nil, fmt.Errorf("payload is %d bytes, %d expected: the file is truncated or corrupted", len(payload), header.PayloadLength)
+  }
+  if checksum := crc32.Checksum(payload, crcTable); checksum != header.Checksum {
+    return nil, nil, fmt.Errorf("payload checksum %08x does not match %08x from the header", checksum, header.Checksum)
+  }
+  return header, payload, nil
+}
+
+func decodeSuggest(b []byte) (*stpb.SuggestData, *FileHeader, error) {
+  header, payload, err := ReadHeader(b)
+  if err != nil {
+    return nil, nil, err
+  }
+  if header.FormatVersion == 0 {
+    log.Printf("suggest data has no header, loading it without the checksum verification")
+  }
+  suggestData := &stpb.SuggestData{}
+  if err := proto.Unmarshal(payload, suggestData); err != nil {
+    return nil, nil, err
+  }
+  return suggestData, header, nil
+}
+
+// ValidateSuggest checks the structure lookups rely on: the descendant keys are unique bytes matching the descendant
+// tries, every item index is in range and the weights of every class list are sorted. The descendant keys of files
+// with the header are also required to be sorted.
+func ValidateSuggest(suggestData *stpb.SuggestData, formatVersion uint32) error {
+  if suggestData.Trie == nil {
+    return fmt.Errorf("suggest data has no trie")
+  }
+  for idx, item := range suggestData.Items {
+    if item == nil {
+      return fmt.Errorf("item #%d is empty", idx)
+    }
+  }
+  return validateTrie(suggestData.Trie, nil, len(suggestData.Items), formatVersion >= 1)
+}
+
+func validateTrie(trie *stpb.SuggestTrie, prefix []byte, itemsCount int, sortedKeys bool) error {
+  if len(trie.DescendantKeys) != len(trie.DescendantTries) {
+    return fmt.Errorf("node %q: %d descendant keys for %d descendants", prefix, len(trie.DescendantKeys), len(trie.DescendantTries))
+  }
+  seenKeys := map[uint32]bool{}
+  for idx, key := range trie.DescendantKeys {
+    if key > math.MaxUint8 {
+      return fmt.Errorf("node %q: descendant key %d is not a byte", prefix, key)
+    }
+    if seenKeys[key] {
+      return fmt.Errorf("node %q: duplicate descendant key %q", prefix, byte(key))
+    }
+    seenKeys[key] = true
+    if sortedKeys && idx > 0 && key < trie.DescendantKeys[idx-1] {
+      return fmt.Errorf("node %q: descendant keys are not sorted", prefix)
+    }
+  }
+  for _, classItems := range trie.Items {
+    if len(classItems.ItemWeights) != len(classItems.ItemIndexes) {
+      return fmt.Errorf("node %q, class %q: %d weights for %d items", prefix, classItems.Class, len(classItems.ItemWeights), len(classItems.ItemIndexes))
+    }
+    if len(classItems.ItemMatchTypes) != 0 && len(classItems.ItemMatchTypes) != len(classItems.ItemIndexes) {
+      return fmt.Errorf("node %q, class %q: %d match types for %d items", prefix, classItems.Class, len(classItems.ItemMatchTypes), len(classItems.ItemIndexes))
+    }
+    for _, itemIdx := range classItems.ItemIndexes {
+      if int(itemIdx) >= itemsCount {
+        return fmt.Errorf("node %q, class %q: item index %d is out of range, %d items", prefix, classItems.Class, itemIdx, itemsCount)
+      }
+    }
+    for idx, weight := range classItems.ItemWeights {
+      if math.IsNaN(float64(weight)) {
+        return fmt.Errorf("node %q, class %q: weight #%d is NaN", prefix, classItems.Class, idx)
+      }
+      if idx > 0 && weight > classItems.ItemWeights[idx-1] {
+        return fmt.Errorf("node %q, class %q: weights are not sorted", prefix, classItems.Class)
+      }
+    }
+  }
+  for idx, descendant := range trie.DescendantTries {
+    if descendant == nil {
+      return fmt.Errorf("node %q: descendant %q is empty", prefix, byte(trie.DescendantKeys[idx]))
+    }
+    if err := validateTrie(descendant, append(prefix, byte(trie.DescendantKeys[idx])), itemsCount, sortedKeys); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+type VerifyReport struct {
+  FormatVersion uint32
+  Version       uint64
+  ItemsCount    int
+  NodesCount    int
+}
+
+// VerifySuggest decodes and validates the file content without serving it.
+func VerifySuggest(b []byte) (*VerifyReport, error) {
+  suggestData, header, err := decodeSuggest(b)
+  if err != nil {
+    return nil, err
+  }
+  if err := ValidateSuggest(suggestData, header.FormatVersion); err != nil {
+    return nil, err
+  }
+  return &VerifyReport{
+    FormatVersion: header.FormatVersion,
+    Version:       suggestData.Version,
+    ItemsCount:    len(suggestData.Items),
+    NodesCount:    CountNodes(suggestData.Trie),
+  }, nil
+}
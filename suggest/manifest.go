@@ -0,0 +1,214 @@
+package suggest
+
+import (
+  "crypto/sha256"
+  "encoding/hex"
+  "encoding/json"
+  "fmt"
+  stpb "main/proto/suggest/suggest_trie"
+  "os"
+  "path/filepath"
+  "strings"
+  "time"
+)
+
+const (
+  ManifestFormatVersion = 1
+  ManifestFileName      = "manifest.json"
+  DefaultDataFileName   = "suggest.data"
+
+  DefaultNormalization     = "default"
+  EqualShapedNormalization = "equal-shaped"
+)
+
+type BuildParameters struct {
+  MaxItemsPerPrefix    int      `json:"count"`
+  SuffixFactor         float64  `json:"suffix_factor"`
+  BuildWithoutSuffixes bool     `json:"build_without_suffixes"`
+  Normalization        string   `json:"normalization"`
+  Facets               []string `json:"facets,omitempty"`
+  StoredOnlyFields     []string `json:"stored_only_fields,omitempty"`
+}
+
+func (bp *BuildParameters) EqualShapedNormalize() bool {
+  return bp != nil && bp.Normalization == EqualShapedNormalization
+}
+
+type ShardingInfo struct {
+  Strategy   string            `json:"strategy"`
+  Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+type ManifestFile struct {
+  Path       string   `json:"path"`
+  Checksum   string   `json:"checksum"`
+  Size       int      `json:"size"`
+  ItemsCount int      `json:"items_count"`
+  NodesCount int      `json:"nodes_count"`
+  ShardKeys  []string `json:"shard_keys,omitempty"`
+}
+
+type Manifest struct {
+  FormatVersion int              `json:"format_version"`
+  Version       uint64           `json:"version"`
+  CreatedAt     time.Time        `json:"created_at"`
+  Parameters    *BuildParameters `json:"parameters"`
+  Sharding      *ShardingInfo    `json:"sharding,omitempty"`
+  Files         []*ManifestFile  `json:"files"`
+
+  dir string
+}
+
+func NewManifest(version uint64, parameters *BuildParameters) *Manifest {
+  return &Manifest{
+    FormatVersion: ManifestFormatVersion,
+    Version:       version,
+    CreatedAt:     time.Now().UTC(),
+    Parameters:    parameters,
+  }
+}
+
+// ReadManifest reads a manifest given either its path or the build output directory holding it.
+func ReadManifest(manifestPath string) (*Manifest, error) {
+  if info, err := os.Stat(manifestPath); err == nil && info.IsDir() {
+    manifestPath = filepath.Join(manifestPath, ManifestFileName)
+  }
+  b, err := os.ReadFile(manifestPath)
+  if err != nil {
+    return nil, err
+  }
+  manifest := &Manifest{}
+  if err := json.Unmarshal(b, manifest); err != nil {
+    return nil, fmt.Errorf("cannot parse manifest %s: %v", manifestPath, err)
+  }
+  if manifest.FormatVersion > ManifestFormatVersion {
+    return nil, fmt.Errorf("manifest %s has format version %d, at most %d is supported", manifestPath, manifest.FormatVersion, ManifestFormatVersion)
+  }
+  if len(manifest.Files) == 0 {
+    return nil, fmt.Errorf("manifest %s lists no files", manifestPath)
+  }
+  manifest.dir = filepath.Dir(manifestPath)
+  return manifest, nil
+}
+
+func (m *Manifest) Write(manifestPath string) error {
+  b, err := json.MarshalIndent(m, "", "  ")
+  if err != nil {
+    return err
+  }
+  return os.WriteFile(manifestPath, b, 0644)
+}
+
+// FilePath resolves the path of a listed file, which is relative to the manifest location.
+func (m *Manifest) FilePath(file *ManifestFile) string {
+  if filepath.IsAbs(file.Path) {
+    return file.Path
+  }
+  return filepath.Join(m.dir, file.Path)
+}
+
+// NewManifestFile describes the data file written to dataPath, with the path stored relative to the manifest.
+func NewManifestFile(manifestPath, dataPath string, b []byte, suggestData *stpb.SuggestData) (*ManifestFile, error) {
+  absManifestDir, err := filepath.Abs(filepath.Dir(manifestPath))
+  if err != nil {
+    return nil, err
+  }
+  absDataPath, err := filepath.Abs(dataPath)
+  if err != nil {
+    return nil, err
+  }
+  relativePath, err := filepath.Rel(absManifestDir, absDataPath)
+  if err != nil {
+    return nil, err
+  }
+  return &ManifestFile{
+    Path:       relativePath,
+    Checksum:   Checksum(b),
+    Size:       len(b),
+    ItemsCount: len(suggestData.Items),
+    NodesCount: CountNodes(suggestData.Trie),
+  }, nil
+}
+
+func (m *Manifest) readFile(file *ManifestFile) ([]byte, error) {
+  filePath := m.FilePath(file)
+  b, err := os.ReadFile(filePath)
+  if err != nil {
+    return nil, err
+  }
+  if checksum := Checksum(b); checksum != file.Checksum {
+    return nil, fmt.Errorf("checksum mismatch for %s: %s in manifest, %s on disk", filePath, file.Checksum, checksum)
+  }
+  return b, nil
+}
+
+// LoadFile loads a listed file, rejecting it if its content does not match the manifest checksum.
+func (m *Manifest) LoadFile(file *ManifestFile) (*stpb.SuggestData, error) {
+  b, err := m.readFile(file)
+  if err != nil {
+    return nil, err
+  }
+  return UnmarshalSuggest(b)
+}
+
+func (m *Manifest) VerifyFile(file *ManifestFile) (*VerifyReport, error) {
+  b, err := m.readFile(file)
+  if err != nil {
+    return nil, err
+  }
+  report, err := VerifySuggest(b)
+  if err != nil {
+    return nil, err
+  }
+  if report.Version != m.Version {
+    return nil, fmt.Errorf("version %d does not match %d from the manifest", report.Version, m.Version)
+  }
+  return report, nil
+}
+
+func Checksum(b []byte) string {
+  sum := sha256.Sum256(b)
+  return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func CountNodes(trie *stpb.SuggestTrie) int {
+  if trie == nil {
+    return 0
+  }
+  count := 1
+  for _, descendant := range trie.DescendantTries {
+    count += CountNodes(descendant)
+  }
+  return count
+}
+
+// OutputLayout tells where a build puts its data files and manifest: either into an output directory
+// or next to the legacy --suggest path.
+type OutputLayout struct {
+  DataPath     string
+  ManifestPath string
+}
+
+func NewOutputLayout(outputDir, suggestDataPath string) *OutputLayout {
+  if outputDir != "" {
+    return &OutputLayout{
+      DataPath:     filepath.Join(outputDir, DefaultDataFileName),
+      ManifestPath: filepath.Join(outputDir, ManifestFileName),
+    }
+  }
+  return &OutputLayout{
+    DataPath:     suggestDataPath,
+    ManifestPath: suggestDataPath + "." + ManifestFileName,
+  }
+}
+
+func (ol *OutputLayout) Prepare() error {
+  return os.MkdirAll(filepath.Dir(ol.ManifestPath), 0755)
+}
+
+// ShardDataPath inserts the shard number before the extension of the file name, e.g. data/suggest_3.data.
+func ShardDataPath(suggestDataPath string, shardNumber int) string {
+  dir, name := filepath.Split(suggestDataPath)
+  ext := filepath.Ext(name)
+  return dir + strings.TrimSuffix(name, ext) + fmt.Sprintf("_%d", shardNumber) + ext
+}
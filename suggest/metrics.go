@@ -0,0 +1,93 @@
+package suggest
+
+import (
+  "main/metrics"
+  "main/network"
+  "net/http"
+  "strings"
+  "time"
+)
+
+var (
+  queriesCount = metrics.Default.NewCounterVec(
+    "suggest_queries_total", "Number of suggest queries.", "index", "endpoint", "class")
+  emptyQueriesCount = metrics.Default.NewCounterVec(
+    "suggest_empty_queries_total", "Number of suggest queries with no suggestions found.", "index", "endpoint", "class")
+  queryDuration = metrics.Default.NewHistogramVec(
+    "suggest_query_duration_seconds", "Suggest query latency.", metrics.DefaultLatencyBuckets, "index", "endpoint", "class")
+  badRequestsCount = metrics.Default.NewCounterVec(
+    "suggest_bad_requests_total", "Number of rejected suggest requests.", "index", "endpoint")
+  indexItemsCount = metrics.Default.NewGaugeVec(
+    "suggest_index_items", "Number of items in the served index.", "index")
+  indexVersion = metrics.Default.NewGaugeVec(
+    "suggest_index_version", "Version of the served index.", "index")
+)
+
+// OtherClassLabel labels the class filters naming the classes the index does not have.
+const OtherClassLabel = "other"
+
+// MultipleClassLabel labels the class filters naming more than one distinct class.
+const MultipleClassLabel = "multiple"
+
+// ClassLabel is the class filter of a query as a metrics label: the class or the excluded class marked with a minus,
+// empty when there is no filter. The filters naming more than one distinct class are labeled as multiple and the
+// filters with classes which are not known as other, so that the number of the label values stays within twice
+// the number of the known classes plus three.
+func ClassLabel(classes, excludeClasses []string, known func(class string) bool) string {
+  seen := map[string]bool{}
+  var label string
+  for _, class := range classes {
+    class = strings.ToLower(class)
+    if !known(class) {
+      return OtherClassLabel
+    }
+    seen[class] = true
+    label = class
+  }
+  for _, class := range excludeClasses {
+    class = strings.ToLower(class)
+    if !known(class) {
+      return OtherClassLabel
+    }
+    seen["-"+class] = true
+    label = "-" + class
+  }
+  if len(seen) > 1 {
+    return MultipleClassLabel
+  }
+  return label
+}
+
+func (h *Handler) hasClass(class string) bool {
+  return h.classes[class]
+}
+
+// ObserveQuery records the latency of the query and whether anything was found for it.
+func (h *Handler) ObserveQuery(endpoint string, q *SuggestQuery, suggestionsCount int, start time.Time) {
+  class := ClassLabel(q.Classes, q.ExcludeClasses, h.hasClass)
+  queriesCount.With(h.Name, endpoint, class).Inc()
+  if suggestionsCount == 0 {
+    emptyQueriesCount.With(h.Name, endpoint, class).Inc()
+  }
+  queryDuration.With(h.Name, endpoint, class).Observe(time.Since(start).Seconds())
+}
+
+func (h *Handler) ObserveBadRequest(endpoint string) {
+  badRequestsCount.With(h.Name, endpoint).Inc()
+}
+
+func (h *Handler) reportBadRequest(w http.ResponseWriter, endpoint, message string) {
+  h.ObserveBadRequest(endpoint)
+  network.ReportBadRequest(w, message)
+}
+
+// SetIndexMetrics reports the size and the version of the served index.
+func (h *Handler) SetIndexMetrics() {
+  indexItemsCount.With(h.Name).Set(float64(len(h.Suggest.Items)))
+  indexVersion.With(h.Name).Set(float64(h.Suggest.Version))
+}
+
+func DeleteIndexMetrics(name string) {
+  indexItemsCount.Delete(name)
+  indexVersion.Delete(name)
+}
@@ -0,0 +1,158 @@
+package suggest
+
+import (
+  "encoding/json"
+  "fmt"
+  "google.golang.org/protobuf/types/known/structpb"
+  stpb "main/proto/suggest/suggest_trie"
+  "strings"
+)
+
+const dataFieldPrefix = "data."
+
+// Projection selects the parts of the suggestions to respond with: weight, text, match_type, data for
+// the whole data or data.<key> for single data keys.
+type Projection struct {
+  Weight    bool
+  Text      bool
+  MatchType bool
+  AllData   bool
+  DataKeys  map[string]bool
+}
+
+// ParseProjection parses the comma-separated field lists, nil means the full suggestions.
+func ParseProjection(values []string) (*Projection, error) {
+  var fields []string
+  for _, value := range values {
+    for _, field := range strings.Split(value, ",") {
+      if field = strings.TrimSpace(field); field != "" {
+        fields = append(fields, field)
+      }
+    }
+  }
+  if len(fields) == 0 {
+    return nil, nil
+  }
+  projection := &Projection{DataKeys: map[string]bool{}}
+  for _, field := range fields {
+    switch {
+    case field == "weight":
+      projection.Weight = true
+    case field == "text":
+      projection.Text = true
+    case field == "match_type":
+      projection.MatchType = true
+    case field == "data":
+      projection.AllData = true
+    case strings.HasPrefix(field, dataFieldPrefix) && len(field) > len(dataFieldPrefix):
+      projection.DataKeys[strings.TrimPrefix(field, dataFieldPrefix)] = true
+    default:
+      return nil, fmt.Errorf("unknown field %q, expected weight, text, match_type, data or data.<key>", field)
+    }
+  }
+  return projection, nil
+}
+
+// Apply projects the suggestions in place. It should go after the pagination, which needs the item ids.
+func (p *Projection) Apply(suggestions []*SuggestAnswerItem) {
+  if p == nil {
+    return
+  }
+  for _, item := range suggestions {
+    item.projection = p
+    if p.AllData {
+      continue
+    }
+    data := map[string]interface{}{}
+    for key := range p.DataKeys {
+      if value, ok := item.Data[key]; ok {
+        data[key] = value
+      }
+    }
+    item.Data = data
+  }
+}
+
+type projectedItem struct {
+  Weight     *float32               `json:"weight,omitempty"`
+  Data       map[string]interface{} `json:"data,omitempty"`
+  TextBlocks []*SuggestionTextBlock `json:"text,omitempty"`
+  MatchType  string                 `json:"match_type,omitempty"`
+  Features   *Features              `json:"features,omitempty"`
+}
+
+func (item *SuggestAnswerItem) MarshalJSON() ([]byte, error) {
+  type plainItem SuggestAnswerItem
+  p := item.projection
+  if p == nil {
+    return json.Marshal((*plainItem)(item))
+  }
+  projected := &projectedItem{Features: item.Features}
+  if p.Weight {
+    projected.Weight = &item.Weight
+  }
+  if p.AllData || len(p.DataKeys) > 0 {
+    projected.Data = item.Data
+  }
+  if p.Text {
+    projected.TextBlocks = item.TextBlocks
+  }
+  if p.MatchType {
+    projected.MatchType = item.MatchType
+  }
+  return json.Marshal(projected)
+}
+
+// reservedDataFields are used by the suggest itself and have to stay in the returned data.
+var reservedDataFields = map[string]bool{
+  "id":           true,
+  "class":        true,
+  "group":        true,
+  CreatedAtField: true,
+  ExpiresAtField: true,
+}
+
+// ValidateStoredOnlyFields checks that none of the fields is used by the suggest itself.
+func ValidateStoredOnlyFields(fields []string) error {
+  for _, field := range fields {
+    if reservedDataFields[field] {
+      return fmt.Errorf("data field %q is used by the suggest and cannot be stored only", field)
+    }
+  }
+  return nil
+}
+
+// SplitStoredData moves the given data fields of every item to its stored data, which is kept in the index
+// for filtering, item lookups and exports but is never returned with the suggestions.
+func SplitStoredData(suggestData *stpb.SuggestData, fields []string) error {
+  if err := ValidateStoredOnlyFields(fields); err != nil {
+    return err
+  }
+  suggestData.StoredOnlyFields = fields
+  for _, item := range suggestData.Items {
+    if item.Data == nil {
+      continue
+    }
+    for _, field := range fields {
+      value, ok := item.Data.Fields[field]
+      if !ok {
+        continue
+      }
+      if item.StoredData == nil {
+        item.StoredData = &structpb.Struct{Fields: map[string]*structpb.Value{}}
+      }
+      item.StoredData.Fields[field] = value
+      delete(item.Data.Fields, field)
+    }
+  }
+  return nil
+}
+
+// FullItemData returns both the returned and the stored-only data of the item.
+func FullItemData(item *stpb.Item) map[string]interface{} {
+  data := item.GetData().AsMap()
+  for key, value := range item.GetStoredData().AsMap() {
+    data[key] = value
+  }
+  return data
+}
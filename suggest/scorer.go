@@ -0,0 +1,116 @@
+package suggest
+
+import (
+  "encoding/json"
+  "fmt"
+  "math"
+  "os"
+  "strings"
+  "unicode/utf8"
+)
+
+// Features describe how well an item matches the query. The texts are compared after normalization.
+type Features struct {
+  Weight        float64 `json:"weight"`
+  LogWeight     float64 `json:"log_weight"`
+  ExactMatch    float64 `json:"exact_match"`
+  PrefixMatch   float64 `json:"prefix_match"`
+  WordBoundary  float64 `json:"word_boundary"`
+  MatchPosition float64 `json:"match_position"`
+  LengthRatio   float64 `json:"length_ratio"`
+  ClassPriority float64 `json:"class_priority"`
+}
+
+func boolFeature(value bool) float64 {
+  if value {
+    return 1
+  }
+  return 0
+}
+
+// NewFeatures computes the features of the suggested item. The match position is the number of words
+// before the matched part, the word count of the text when the part is not found at all.
+func NewFeatures(normalizedPart, normalizedText string, item *SuggestAnswerItem, classPriorities map[string]float64) *Features {
+  features := &Features{
+    Weight:        float64(item.Weight),
+    LogWeight:     math.Log1p(math.Max(float64(item.Weight), 0)),
+    ExactMatch:    boolFeature(normalizedText == normalizedPart),
+    PrefixMatch:   boolFeature(strings.HasPrefix(normalizedText, normalizedPart)),
+    ClassPriority: classPriorities[item.Class()],
+  }
+  if pos := strings.Index(normalizedText, normalizedPart); pos >= 0 {
+    end := pos + len(normalizedPart)
+    features.WordBoundary = boolFeature(end == len(normalizedText) || normalizedText[end] == ' ')
+    features.MatchPosition = float64(strings.Count(normalizedText[:pos], " "))
+  } else {
+    features.MatchPosition = float64(len(strings.Fields(normalizedText)))
+  }
+  if textLength := utf8.RuneCountInString(normalizedText); textLength > 0 {
+    features.LengthRatio = float64(utf8.RuneCountInString(normalizedPart)) / float64(textLength)
+  }
+  return features
+}
+
+func (f *Features) Values() map[string]float64 {
+  return map[string]float64{
+    "weight":         f.Weight,
+    "log_weight":     f.LogWeight,
+    "exact_match":    f.ExactMatch,
+    "prefix_match":   f.PrefixMatch,
+    "word_boundary":  f.WordBoundary,
+    "match_position": f.MatchPosition,
+    "length_ratio":   f.LengthRatio,
+    "class_priority": f.ClassPriority,
+  }
+}
+
+// Scorer computes the ranking score of a retrieved item, the suggestions are returned by the score
+// in descending order.
+type Scorer interface {
+  Score(features *Features) float64
+}
+
+// WeightScorer ranks by the weight stored for the prefix, which is the order of the index itself.
+type WeightScorer struct{}
+
+func (WeightScorer) Score(features *Features) float64 {
+  return features.Weight
+}
+
+// LinearScorer is a linear model over the named features.
+type LinearScorer struct {
+  Bias            float64            `json:"bias"`
+  Coefficients    map[string]float64 `json:"coefficients"`
+  ClassPriorities map[string]float64 `json:"class_priorities"`
+}
+
+func (ls *LinearScorer) Score(features *Features) float64 {
+  score := ls.Bias
+  for name, value := range features.Values() {
+    score += ls.Coefficients[name] * value
+  }
+  return score
+}
+
+func LoadLinearScorer(path string) (*LinearScorer, error) {
+  b, err := os.ReadFile(path)
+  if err != nil {
+    return nil, err
+  }
+  scorer := &LinearScorer{}
+  if err := json.Unmarshal(b, scorer); err != nil {
+    return nil, err
+  }
+  known := (&Features{}).Values()
+  for name := range scorer.Coefficients {
+    if _, ok := known[name]; !ok {
+      return nil, fmt.Errorf("unknown feature %q", name)
+    }
+  }
+  classPriorities := map[string]float64{}
+  for class, priority := range scorer.ClassPriorities {
+    classPriorities[strings.ToLower(class)] = priority
+  }
+  scorer.ClassPriorities = classPriorities
+  return scorer, nil
+}
@@ -1,7 +1,8 @@
 package suggest
 
 import (
-  "google.golang.org/protobuf/proto"
+  "container/heap"
+  "fmt"
   "google.golang.org/protobuf/types/known/structpb"
   "log"
   stpb "main/proto/suggest/suggest_trie"
@@ -21,10 +22,45 @@ type SuggestAnswerItem struct {
   Weight     float32                `json:"weight"`
   Data       map[string]interface{} `json:"data"`
   TextBlocks []*SuggestionTextBlock `json:"text"`
+  MatchType  string                 `json:"match_type,omitempty"`
+  Features   *Features              `json:"features,omitempty"`
+  // ItemId, OriginalText and NormalizedText come from the suggested index item. They are not part of the json
+  // responses, so the suggestions read from them fall back to the data id and the highlighted text.
+  ItemId         string `json:"-"`
+  OriginalText   string `json:"-"`
+  NormalizedText string `json:"-"`
+
+  projection *Projection
+}
+
+// Text returns the original text of the suggested item.
+func (item *SuggestAnswerItem) Text() string {
+  if item.OriginalText != "" {
+    return item.OriginalText
+  }
+  var text strings.Builder
+  for _, block := range item.TextBlocks {
+    text.WriteString(block.Text)
+  }
+  return text.String()
+}
+
+// Id returns the "id" field of the item data if there is one and the original text otherwise.
+func (item *SuggestAnswerItem) Id() string {
+  if item.ItemId != "" {
+    return item.ItemId
+  }
+  return idOrText(item.Data["id"], item.Text())
+}
+
+func (item *SuggestAnswerItem) Class() string {
+  class, _ := item.Data["class"].(string)
+  return strings.ToLower(class)
 }
 
 type SuggestResponse struct {
-  Suggestions []*SuggestAnswerItem `json:"suggestions"`
+  Suggestions     []*SuggestAnswerItem `json:"suggestions"`
+  TotalItemsCount int                  `json:"total_items_count,omitempty"`
 }
 
 type PaginatedSuggestResponse struct {
@@ -32,6 +68,7 @@ type PaginatedSuggestResponse struct {
   PageNumber      int                  `json:"page_number"`
   TotalPagesCount int                  `json:"total_pages_count"`
   TotalItemsCount int                  `json:"total_items_count"`
+  NextCursor      string               `json:"next_cursor,omitempty"`
 }
 
 type ProtoTransformer struct {
@@ -55,6 +92,7 @@ func (pt *ProtoTransformer) TransformTrie(builder *SuggestTrieBuilder) (*stpb.Su
     trie.DescendantKeys = append(trie.DescendantKeys, uint32(d.Key))
     trie.DescendantTries = append(trie.DescendantTries, descendant)
   }
+  trie.TotalItemsCount = uint32(builder.TotalItemsCount)
   for _, suggest := range builder.Suggest {
     trieItems := &stpb.ClassItems{
       Class: suggest.Class,
@@ -67,13 +105,15 @@ func (pt *ProtoTransformer) TransformTrie(builder *SuggestTrieBuilder) (*stpb.Su
         }
         pt.ItemsMap[item.OriginalItem] = len(pt.Items)
         pt.Items = append(pt.Items, &stpb.Item{
-          Weight:       item.OriginalItem.Weight,
-          OriginalText: item.OriginalItem.OriginalText,
-          Data:         dataStruct,
+          Weight:         item.OriginalItem.Weight,
+          OriginalText:   item.OriginalItem.OriginalText,
+          NormalizedText: item.OriginalItem.NormalizedText,
+          Data:           dataStruct,
         })
       }
       trieItems.ItemWeights = append(trieItems.ItemWeights, item.Weight)
       trieItems.ItemIndexes = append(trieItems.ItemIndexes, uint32(pt.ItemsMap[item.OriginalItem]))
+      trieItems.ItemMatchTypes = append(trieItems.ItemMatchTypes, item.MatchType)
     }
     trie.Items = append(trie.Items, trieItems)
   }
@@ -100,7 +140,14 @@ func BuildSuggestData(
 ) (*stpb.SuggestData, error) {
   overheadItemsCount := maxItemsPerPrefix * 2
   builder := &SuggestTrieBuilder{}
+  now := time.Now()
+  expiredItemsCount := 0
   for idx, item := range items {
+    if item.Expired(now) {
+      expiredItemsCount++
+      continue
+    }
+
     builder.Add(0, item.NormalizedText, overheadItemsCount, &SuggestTrieItem{
       Weight:       item.Weight,
       OriginalItem: item,
@@ -112,6 +159,7 @@ func BuildSuggestData(
         builder.Add(0, strings.Join(parts[i:], " "), overheadItemsCount, &SuggestTrieItem{
           Weight:       item.Weight * postfixWeightFactor,
           OriginalItem: item,
+          MatchType:    stpb.MatchType_SuffixMatch,
         })
       }
     }
@@ -120,6 +168,9 @@ func BuildSuggestData(
       log.Printf("added %d items of %d to suggest", idx+1, len(items))
     }
   }
+  if expiredItemsCount > 0 {
+    log.Printf("skipped %d expired items", expiredItemsCount)
+  }
   log.Printf("finalizing suggest")
   builder.Finalize(maxItemsPerPrefix)
   return Transform(builder)
@@ -155,7 +206,7 @@ func doHighlight(originalPart string, originalSuggest string) []*SuggestionTextB
   return textBlocks
 }
 
-func GetSuggestItems(suggest *stpb.SuggestData, prefix []byte, classes, excludeClasses map[string]bool) []*stpb.Item {
+func findTrie(suggest *stpb.SuggestData, prefix []byte) *stpb.SuggestTrie {
   trie := suggest.Trie
   for _, c := range prefix {
     found := false
@@ -171,69 +222,380 @@ func GetSuggestItems(suggest *stpb.SuggestData, prefix []byte, classes, excludeC
       return nil
     }
   }
+  return trie
+}
+
+// findPrefixTrie finds the node keeping the items of the prefix, skipping the chain of single descendants
+// the builder leaves without items.
+func findPrefixTrie(suggest *stpb.SuggestData, prefix []byte) *stpb.SuggestTrie {
+  trie := findTrie(suggest, prefix)
+  if trie == nil {
+    return nil
+  }
   for len(trie.DescendantKeys) == 1 && len(trie.Items) == 0 {
-    for _, d := range trie.DescendantTries {
-      trie = d
-      break
+    trie = trie.DescendantTries[0]
+  }
+  return trie
+}
+
+var matchTypeNames = map[stpb.MatchType]string{
+  stpb.MatchType_PrefixMatch: "prefix",
+  stpb.MatchType_SuffixMatch: "suffix",
+}
+
+func MatchTypeName(matchType stpb.MatchType) string {
+  return matchTypeNames[matchType]
+}
+
+// ParseMatchType returns the match type of the name, the prefix match for the unknown ones.
+func ParseMatchType(name string) stpb.MatchType {
+  for matchType, matchTypeName := range matchTypeNames {
+    if matchTypeName == name {
+      return matchType
+    }
+  }
+  return stpb.MatchType_PrefixMatch
+}
+
+// PrefixItem is an item found for a prefix along with the weight and the match type stored for that prefix.
+type PrefixItem struct {
+  Item      *stpb.Item
+  Weight    float32
+  MatchType stpb.MatchType
+}
+
+// itemMatchType returns the stored match type. The files built before match types were stored keep
+// the suffix matches with the weight reduced by the suffix factor, so the type is derived from the weights.
+func itemMatchType(suggestItems *stpb.ClassItems, idx int, item *stpb.Item) stpb.MatchType {
+  if len(suggestItems.ItemMatchTypes) == len(suggestItems.ItemIndexes) {
+    return suggestItems.ItemMatchTypes[idx]
+  }
+  if suggestItems.ItemWeights[idx] < item.Weight {
+    return stpb.MatchType_SuffixMatch
+  }
+  return stpb.MatchType_PrefixMatch
+}
+
+// ItemsQuery restricts the items looked up for a prefix. The nodes keep a limited number of items, so
+// when the ones of the prefix node do not fill Count after the filtering, the descendant nodes are searched
+// best-first by their top weight, visiting at most NodesBudget of them. Deep queries walk the subtree the same way
+// up to MaxDeepNodes nodes, until Count items past the After cursor are collected.
+type ItemsQuery struct {
+  Classes        map[string]bool
+  ExcludeClasses map[string]bool
+  Filters        []*Filter
+  Now            time.Time
+  Count          int
+  NodesBudget    int
+  Deep           bool
+  After          *Cursor
+}
+
+const (
+  DefaultNodesBudget = 256
+  MaxDeepNodes       = 16384
+)
+
+func (q *ItemsQuery) acceptsClass(class string) bool {
+  if q.ExcludeClasses[class] {
+    return false
+  }
+  return len(q.Classes) == 0 || q.Classes[class]
+}
+
+type itemsCollector struct {
+  suggest   *stpb.SuggestData
+  query     *ItemsQuery
+  facets    map[string]bool
+  items     []*PrefixItem
+  seenItems map[uint32]*PrefixItem
+  checked   map[uint32]bool
+  best      itemsHeap
+  inBest    map[*PrefixItem]bool
+}
+
+// itemsHeap keeps the best Count collected items with the worst of them on top, in the pagination order:
+// by weight, then by id.
+type itemsHeap []*PrefixItem
+
+func (h itemsHeap) Len() int {
+  return len(h)
+}
+
+func (h itemsHeap) Less(i, j int) bool {
+  return worse(h[i], h[j])
+}
+
+func worse(a, b *PrefixItem) bool {
+  if a.Weight != b.Weight {
+    return a.Weight < b.Weight
+  }
+  return ItemId(a.Item) > ItemId(b.Item)
+}
+
+func (h itemsHeap) Swap(i, j int) {
+  h[i], h[j] = h[j], h[i]
+}
+
+func (h *itemsHeap) Push(x interface{}) {
+  *h = append(*h, x.(*PrefixItem))
+}
+
+func (h *itemsHeap) Pop() interface{} {
+  old := *h
+  item := old[len(old)-1]
+  *h = old[:len(old)-1]
+  return item
+}
+
+// offer keeps the item among the best ones when there is room or it is better than the worst of them.
+func (c *itemsCollector) offer(item *PrefixItem) {
+  if c.query.Count <= 0 {
+    return
+  }
+  if c.query.After != nil && c.query.After.precedes(item.Weight, ItemId(item.Item)) {
+    return
+  }
+  if len(c.best) < c.query.Count {
+    heap.Push(&c.best, item)
+    c.inBest[item] = true
+    return
+  }
+  if worse(c.best[0], item) {
+    delete(c.inBest, c.best[0])
+    c.best[0] = item
+    c.inBest[item] = true
+    heap.Fix(&c.best, 0)
+  }
+}
+
+// remove takes the item out of the best ones before its weight changes. It rarely happens, only for the items
+// stored more than once, so the item is looked up by a scan.
+func (c *itemsCollector) remove(item *PrefixItem) {
+  if !c.inBest[item] {
+    return
+  }
+  delete(c.inBest, item)
+  for i, bestItem := range c.best {
+    if bestItem == item {
+      heap.Remove(&c.best, i)
+      return
     }
   }
-  var items []*stpb.Item
+}
+
+func (c *itemsCollector) addNode(trie *stpb.SuggestTrie) {
   for _, suggestItems := range trie.Items {
-    for _, class := range suggestItems.Classes {
-      if _, ok := excludeClasses[class]; ok {
+    if !c.query.acceptsClass(suggestItems.Class) {
+      continue
+    }
+    for idx, itemIdx := range suggestItems.ItemIndexes {
+      c.checked[itemIdx] = true
+      trieItem := c.suggest.Items[itemIdx]
+      if len(c.query.Filters) > 0 && !matchFilters(trieItem, c.query.Filters, c.facets) {
         continue
       }
-      if _, ok := classes[class]; !ok && len(classes) > 0 {
+      if !c.query.Now.IsZero() && itemExpired(trieItem, c.query.Now) {
         continue
       }
+      item := &PrefixItem{
+        Item:      trieItem,
+        Weight:    suggestItems.ItemWeights[idx],
+        MatchType: itemMatchType(suggestItems, idx, trieItem),
+      }
+      if seenItem, ok := c.seenItems[itemIdx]; ok {
+        if item.Weight > seenItem.Weight {
+          c.remove(seenItem)
+          *seenItem = *item
+          c.offer(seenItem)
+        }
+        continue
+      }
+      c.seenItems[itemIdx] = item
+      c.items = append(c.items, item)
+      c.offer(item)
     }
-    if _, ok := classes[suggestItems.Class]; !ok && len(classes) > 0 {
+  }
+}
+
+type weightedNode struct {
+  trie   *stpb.SuggestTrie
+  weight float32
+}
+
+type nodesHeap []*weightedNode
+
+func (h nodesHeap) Len() int {
+  return len(h)
+}
+
+func (h nodesHeap) Less(i, j int) bool {
+  return h[i].weight > h[j].weight
+}
+
+func (h nodesHeap) Swap(i, j int) {
+  h[i], h[j] = h[j], h[i]
+}
+
+func (h *nodesHeap) Push(x interface{}) {
+  *h = append(*h, x.(*weightedNode))
+}
+
+func (h *nodesHeap) Pop() interface{} {
+  old := *h
+  node := old[len(old)-1]
+  *h = old[:len(old)-1]
+  return node
+}
+
+// nodePriority is the upper bound of the weights of the subtree items not collected yet: the highest weight of
+// the items stored in the node which were not checked yet or were collected with a lower weight, e.g. as a suffix
+// match in another node, and the lowest stored weight of every class, which bounds the items of the class the node
+// does not keep. The nodes without items inherit the parent priority.
+func (c *itemsCollector) nodePriority(trie *stpb.SuggestTrie, parentPriority float32) float32 {
+  found := false
+  var priority float32
+  raise := func(weight float32) {
+    if !found || weight > priority {
+      priority = weight
+      found = true
+    }
+  }
+  for _, suggestItems := range trie.Items {
+    if !c.query.acceptsClass(suggestItems.Class) || len(suggestItems.ItemIndexes) == 0 {
       continue
     }
-    for _, itemIdx := range suggestItems.ItemIndexes {
-      items = append(items, suggest.Items[itemIdx])
+    lowest := suggestItems.ItemWeights[0]
+    for idx, itemIdx := range suggestItems.ItemIndexes {
+      weight := suggestItems.ItemWeights[idx]
+      if weight < lowest {
+        lowest = weight
+      }
+      seenItem, seen := c.seenItems[itemIdx]
+      if !c.checked[itemIdx] || (seen && weight > seenItem.Weight) {
+        raise(weight)
+      }
     }
+    raise(lowest)
+  }
+  if found {
+    return priority
+  }
+  return parentPriority
+}
+
+func (c *itemsCollector) fillFromDescendants(trie *stpb.SuggestTrie, nodesBudget int) {
+  nodes := &nodesHeap{}
+  pushDescendants := func(trie *stpb.SuggestTrie, weight float32) {
+    for _, descendant := range trie.DescendantTries {
+      heap.Push(nodes, &weightedNode{trie: descendant, weight: c.nodePriority(descendant, weight)})
+    }
+  }
+  pushDescendants(trie, c.nodePriority(trie, 0))
+  for visited := 0; nodes.Len() > 0 && visited < nodesBudget && !c.filled((*nodes)[0].weight); visited++ {
+    node := heap.Pop(nodes).(*weightedNode)
+    c.addNode(node.trie)
+    pushDescendants(node.trie, node.weight)
+  }
+}
+
+// filled tells whether the count is reached and no unchecked item can outweigh the collected ones or tie with them,
+// the priority being the upper bound of the weights not checked yet.
+func (c *itemsCollector) filled(priority float32) bool {
+  if c.query.Count <= 0 || len(c.best) < c.query.Count {
+    return false
   }
-  sort.Slice(items, func(i, j int) bool {
-    return items[i].Weight > items[j].Weight
+  return c.best[0].Weight > priority
+}
+
+// GetSuggestItems returns the items stored for the prefix which pass the query restrictions, ordered by their
+// weight for the prefix. An item stored more than once, e.g. as both a prefix and a suffix match, is returned
+// once with the highest weight.
+func GetSuggestItems(suggest *stpb.SuggestData, prefix []byte, q *ItemsQuery) []*PrefixItem {
+  if q == nil {
+    q = &ItemsQuery{}
+  }
+  trie := findPrefixTrie(suggest, prefix)
+  if trie == nil {
+    return nil
+  }
+  c := &itemsCollector{
+    suggest:   suggest,
+    query:     q,
+    facets:    map[string]bool{},
+    seenItems: map[uint32]*PrefixItem{},
+    checked:   map[uint32]bool{},
+    inBest:    map[*PrefixItem]bool{},
+  }
+  for _, facet := range suggest.Facets {
+    c.facets[facet] = true
+  }
+  c.addNode(trie)
+  if q.Deep {
+    c.fillFromDescendants(trie, MaxDeepNodes)
+  } else if q.Count > 0 && len(c.items) < q.Count && q.NodesBudget > 0 {
+    c.fillFromDescendants(trie, q.NodesBudget)
+  }
+  sort.SliceStable(c.items, func(i, j int) bool {
+    return c.items[i].Weight > c.items[j].Weight
   })
-  return items
+  return c.items
 }
 
-func GetSuggest(suggest *stpb.SuggestData, originalPart string, normalizedPart string, classes, excludeClasses map[string]bool) []*SuggestAnswerItem {
-  trieItems := GetSuggestItems(suggest, []byte(normalizedPart), classes, excludeClasses)
+// ItemsByText maps the normalized texts of the items to their indexes, keeping the highest weighted item of a text.
+func ItemsByText(suggest *stpb.SuggestData) map[string]uint32 {
+  itemsByText := make(map[string]uint32, len(suggest.Items))
+  for itemIdx, item := range suggest.Items {
+    if foundIdx, ok := itemsByText[item.NormalizedText]; !ok || item.Weight > suggest.Items[foundIdx].Weight {
+      itemsByText[item.NormalizedText] = uint32(itemIdx)
+    }
+  }
+  return itemsByText
+}
+
+func GetSuggest(suggest *stpb.SuggestData, originalPart string, normalizedPart string, q *ItemsQuery) []*SuggestAnswerItem {
+  trieItems := GetSuggestItems(suggest, []byte(normalizedPart), q)
   items := make([]*SuggestAnswerItem, 0)
   if trieItems == nil {
     return items
   }
   for _, trieItem := range trieItems {
     items = append(items, &SuggestAnswerItem{
-      Weight:     trieItem.Weight,
-      Data:       trieItem.Data.AsMap(),
-      TextBlocks: doHighlight(originalPart, trieItem.OriginalText),
+      Weight:         trieItem.Weight,
+      Data:           trieItem.Item.Data.AsMap(),
+      TextBlocks:     doHighlight(originalPart, trieItem.Item.OriginalText),
+      MatchType:      MatchTypeName(trieItem.MatchType),
+      ItemId:         ItemId(trieItem.Item),
+      OriginalText:   trieItem.Item.OriginalText,
+      NormalizedText: trieItem.Item.NormalizedText,
     })
   }
   return items
 }
 
-func LoadSuggest(suggestDataPath string) (*stpb.SuggestData, error) {
-  b, err := os.ReadFile(suggestDataPath)
+func UnmarshalSuggest(b []byte) (*stpb.SuggestData, error) {
+  suggestData, header, err := decodeSuggest(b)
   if err != nil {
     return nil, err
   }
-  suggestData := &stpb.SuggestData{}
-  if err := proto.Unmarshal(b, suggestData); err != nil {
-    return nil, err
+  if err := ValidateSuggest(suggestData, header.FormatVersion); err != nil {
+    return nil, fmt.Errorf("invalid suggest data: %v", err)
   }
   return suggestData, nil
 }
 
+func LoadSuggest(suggestDataPath string) (*stpb.SuggestData, error) {
+  b, err := os.ReadFile(suggestDataPath)
+  if err != nil {
+    return nil, err
+  }
+  return UnmarshalSuggest(b)
+}
+
 func DoBuildSuggest(
   inputFilePath string,
-  suggestDataPath string,
-  maxItemsPerPrefix int,
-  suffixFactor float64,
-  buildWithoutSuffixes bool,
+  layout *OutputLayout,
+  parameters *BuildParameters,
 ) {
   policy := tools.GetPolicy()
   items, err := LoadItems(inputFilePath, policy)
@@ -243,20 +605,38 @@ func DoBuildSuggest(
 
   suggestVersion := uint64(time.Now().Unix())
 
-  suggestData, err := BuildSuggestData(items, maxItemsPerPrefix, float32(suffixFactor), buildWithoutSuffixes)
+  suggestData, err := BuildSuggestData(items, parameters.MaxItemsPerPrefix, float32(parameters.SuffixFactor), parameters.BuildWithoutSuffixes)
   if err != nil {
     log.Fatalln(err)
   }
 
   SetVersion(suggestData, suggestVersion)
+  SetFacets(suggestData, parameters.Facets)
+  if err := SplitStoredData(suggestData, parameters.StoredOnlyFields); err != nil {
+    log.Fatalln(err)
+  }
 
   log.Printf("marshalling suggest as proto")
-  b, err := proto.Marshal(suggestData)
+  b, err := MarshalSuggest(suggestData)
+  if err != nil {
+    log.Fatalln(err)
+  }
+  if err := layout.Prepare(); err != nil {
+    log.Fatalln(err)
+  }
+  log.Printf("writing the resulting proto suggest data to %s", layout.DataPath)
+  if err := os.WriteFile(layout.DataPath, b, 0644); err != nil {
+    log.Fatalln(err)
+  }
+
+  manifestFile, err := NewManifestFile(layout.ManifestPath, layout.DataPath, b, suggestData)
   if err != nil {
     log.Fatalln(err)
   }
-  log.Printf("writing the resulting proto suggest data to %s", suggestDataPath)
-  if err := os.WriteFile(suggestDataPath, b, 0644); err != nil {
+  manifest := NewManifest(suggestVersion, parameters)
+  manifest.Files = append(manifest.Files, manifestFile)
+  log.Printf("writing the build manifest to %s", layout.ManifestPath)
+  if err := manifest.Write(layout.ManifestPath); err != nil {
     log.Fatalln(err)
   }
 }
@@ -6,6 +6,7 @@ import (
   "fmt"
   "github.com/microcosm-cc/bluemonday"
   "log"
+  stpb "main/proto/suggest/suggest_trie"
   "main/tools"
   "os"
   "strconv"
@@ -40,12 +41,14 @@ func NewItem(line string, policy *bluemonday.Policy) (*Item, error) {
   }, nil
 }
 
-func LoadItems(inputFilePath string, policy *bluemonday.Policy) ([]*Item, error) {
+// ScanItems parses the input file line by line, passing every item to the callback without keeping them in memory.
+func ScanItems(inputFilePath string, policy *bluemonday.Policy, callback func(item *Item) error) error {
   file, err := os.Open(inputFilePath)
   if err != nil {
-    return nil, err
+    return err
   }
-  var items []*Item
+  defer file.Close()
+
   scanner := bufio.NewScanner(file)
   lineNumber := 0
   for scanner.Scan() {
@@ -55,13 +58,63 @@ func LoadItems(inputFilePath string, policy *bluemonday.Policy) ([]*Item, error)
     }
     item, err := NewItem(line, policy)
     if err != nil {
-      return nil, fmt.Errorf("error processing line #%d: %v", lineNumber, err)
+      return fmt.Errorf("error processing line #%d: %v", lineNumber, err)
+    }
+    if err := callback(item); err != nil {
+      return err
     }
-    items = append(items, item)
     lineNumber++
     if lineNumber%100000 == 0 {
       log.Printf("read %d lines", lineNumber)
     }
   }
+  return scanner.Err()
+}
+
+func LoadItems(inputFilePath string, policy *bluemonday.Policy) ([]*Item, error) {
+  var items []*Item
+  err := ScanItems(inputFilePath, policy, func(item *Item) error {
+    items = append(items, item)
+    return nil
+  })
+  if err != nil {
+    return nil, err
+  }
   return items, nil
 }
+
+// DataValueString formats a value of the item data json, keeping integral numbers free of exponents.
+func DataValueString(value interface{}) string {
+  switch v := value.(type) {
+  case string:
+    return v
+  case float64:
+    return strconv.FormatFloat(v, 'f', -1, 64)
+  }
+  return fmt.Sprint(value)
+}
+
+// idOrText identifies an item by the "id" field of its data, falling back to the original text when the id is
+// missing or null.
+func idOrText(id interface{}, originalText string) string {
+  if id != nil {
+    return DataValueString(id)
+  }
+  return originalText
+}
+
+// Id returns the "id" field of the item data if there is one and the original text otherwise.
+func (item *Item) Id() string {
+  return idOrText(item.Data["id"], item.OriginalText)
+}
+
+// ItemId identifies an index item across builds the same way Item.Id identifies a build input item.
+func ItemId(item *stpb.Item) string {
+  var id interface{}
+  if item.Data != nil {
+    if value, ok := item.Data.Fields["id"]; ok {
+      id = value.AsInterface()
+    }
+  }
+  return idOrText(id, item.OriginalText)
+}
@@ -2,6 +2,7 @@ package suggest
 
 import (
   "container/heap"
+  stpb "main/proto/suggest/suggest_trie"
   "reflect"
   "sort"
   "strings"
@@ -10,6 +11,7 @@ import (
 type SuggestTrieItem struct {
   Weight       float32
   OriginalItem *Item
+  MatchType    stpb.MatchType
 }
 
 type SuggestTrieDescendant struct {
@@ -45,10 +47,17 @@ func (s *SuggestItems) Pop() interface{} {
   return lastItem
 }
 
+// DeduplicateSuggest keeps the first of the items of the same group and the first occurrence of every item,
+// which is added twice when both the text and one of its suffixes start with the prefix.
 func (s *SuggestItems) DeduplicateSuggest() {
   seenGroups := map[string]bool{}
+  seenItems := map[*Item]bool{}
   var deduplicatedItems []*SuggestTrieItem
   for _, item := range s.Suggest {
+    if seenItems[item.OriginalItem] {
+      continue
+    }
+    seenItems[item.OriginalItem] = true
     group, ok := item.OriginalItem.Data["group"]
     if !ok {
       deduplicatedItems = append(deduplicatedItems, item)
@@ -65,8 +74,11 @@ func (s *SuggestItems) DeduplicateSuggest() {
 }
 
 type SuggestTrieBuilder struct {
-  Descendants []*SuggestTrieDescendant
-  Suggest     []*SuggestItems
+  Descendants     []*SuggestTrieDescendant
+  Suggest         []*SuggestItems
+  TotalItemsCount int
+
+  lastItem *Item
 }
 
 func (s *SuggestTrieBuilder) addItem(maxItemsPerPrefix int, item *SuggestTrieItem) {
@@ -89,7 +101,13 @@ func (s *SuggestTrieBuilder) addItem(maxItemsPerPrefix int, item *SuggestTrieIte
   })
 }
 
+// Add adds the item to the node and to the descendants along the text. The texts of an item, the full one
+// and the suffixes, are expected to be added one after another, so that the item is counted once per node.
 func (s *SuggestTrieBuilder) Add(position int, text string, maxItemsPerPrefix int, item *SuggestTrieItem) {
+  if s.lastItem != item.OriginalItem {
+    s.lastItem = item.OriginalItem
+    s.TotalItemsCount++
+  }
   s.addItem(maxItemsPerPrefix, item)
   if position == len(text) {
     return
@@ -127,6 +145,9 @@ func (s *SuggestTrieBuilder) Finalize(maxItemsPerPrefix int) {
       suggest.Suggest = suggest.Suggest[:maxItemsPerPrefix]
     }
   }
+  sort.Slice(s.Descendants, func(i, j int) bool {
+    return s.Descendants[i].Key < s.Descendants[j].Key
+  })
   for _, descendant := range s.Descendants {
     descendant.Builder.Finalize(maxItemsPerPrefix)
   }
@@ -0,0 +1,129 @@
+package suggest
+
+import (
+  "google.golang.org/protobuf/types/known/structpb"
+  stpb "main/proto/suggest/suggest_trie"
+  "mime"
+  "net/http"
+  "strings"
+)
+
+const ProtoContentType = "application/x-protobuf"
+
+func AcceptsProto(header http.Header) bool {
+  for _, accept := range header.Values("Accept") {
+    for _, mediaRange := range strings.Split(accept, ",") {
+      if mediaType, _, err := mime.ParseMediaType(mediaRange); err == nil && mediaType == ProtoContentType {
+        return true
+      }
+    }
+  }
+  return false
+}
+
+func IsProtoContent(header http.Header) bool {
+  mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+  return err == nil && mediaType == ProtoContentType
+}
+
+func (f *Features) ToProto() *stpb.Features {
+  if f == nil {
+    return nil
+  }
+  return &stpb.Features{
+    Weight:        f.Weight,
+    LogWeight:     f.LogWeight,
+    ExactMatch:    f.ExactMatch,
+    PrefixMatch:   f.PrefixMatch,
+    WordBoundary:  f.WordBoundary,
+    MatchPosition: f.MatchPosition,
+    LengthRatio:   f.LengthRatio,
+    ClassPriority: f.ClassPriority,
+  }
+}
+
+func NewFeaturesFromProto(features *stpb.Features) *Features {
+  if features == nil {
+    return nil
+  }
+  return &Features{
+    Weight:        features.Weight,
+    LogWeight:     features.LogWeight,
+    ExactMatch:    features.ExactMatch,
+    PrefixMatch:   features.PrefixMatch,
+    WordBoundary:  features.WordBoundary,
+    MatchPosition: features.MatchPosition,
+    LengthRatio:   features.LengthRatio,
+    ClassPriority: features.ClassPriority,
+  }
+}
+
+func (r *PaginatedSuggestResponse) ToProto() (*stpb.PaginatedSuggestResponse, error) {
+  response := &stpb.PaginatedSuggestResponse{
+    PageNumber:      int32(r.PageNumber),
+    TotalPagesCount: int32(r.TotalPagesCount),
+    TotalItemsCount: int32(r.TotalItemsCount),
+    NextCursor:      r.NextCursor,
+  }
+  for _, suggestion := range r.Suggestions {
+    dataStruct, err := structpb.NewStruct(suggestion.Data)
+    if err != nil {
+      return nil, err
+    }
+    item := &stpb.SuggestAnswerItem{
+      Weight:       suggestion.Weight,
+      Data:         dataStruct,
+      MatchType:    ParseMatchType(suggestion.MatchType),
+      ItemId:       suggestion.ItemId,
+      OriginalText: suggestion.OriginalText,
+      Features:     suggestion.Features.ToProto(),
+    }
+    // the projected items carry the same fields as in json, the match type is kept as the enum has no unset value
+    textBlocks := suggestion.TextBlocks
+    if p := suggestion.projection; p != nil {
+      item.ItemId, item.OriginalText = "", ""
+      if !p.Weight {
+        item.Weight = 0
+      }
+      if !p.Text {
+        textBlocks = nil
+      }
+    }
+    for _, textBlock := range textBlocks {
+      item.TextBlocks = append(item.TextBlocks, &stpb.SuggestionTextBlock{
+        Text:      textBlock.Text,
+        Highlight: textBlock.Highlight,
+      })
+    }
+    response.Suggestions = append(response.Suggestions, item)
+  }
+  return response, nil
+}
+
+func NewPaginatedSuggestResponseFromProto(response *stpb.PaginatedSuggestResponse) *PaginatedSuggestResponse {
+  paginatedResponse := &PaginatedSuggestResponse{
+    Suggestions:     make([]*SuggestAnswerItem, 0, len(response.Suggestions)),
+    PageNumber:      int(response.PageNumber),
+    TotalPagesCount: int(response.TotalPagesCount),
+    TotalItemsCount: int(response.TotalItemsCount),
+    NextCursor:      response.NextCursor,
+  }
+  for _, item := range response.Suggestions {
+    suggestion := &SuggestAnswerItem{
+      Weight:       item.Weight,
+      Data:         item.Data.AsMap(),
+      MatchType:    MatchTypeName(item.MatchType),
+      ItemId:       item.ItemId,
+      OriginalText: item.OriginalText,
+      Features:     NewFeaturesFromProto(item.Features),
+    }
+    for _, textBlock := range item.TextBlocks {
+      suggestion.TextBlocks = append(suggestion.TextBlocks, &SuggestionTextBlock{
+        Text:      textBlock.Text,
+        Highlight: textBlock.Highlight,
+      })
+    }
+    paginatedResponse.Suggestions = append(paginatedResponse.Suggestions, suggestion)
+  }
+  return paginatedResponse
+}
@@ -0,0 +1,161 @@
+package suggest_feedback
+
+import (
+  "main/suggest"
+  "math"
+  "sort"
+  "sync"
+  "time"
+)
+
+// smoothingPrior is the number of virtual impressions with a 0.5 selection rate every item starts with,
+// so that a few events do not move the weights much.
+const smoothingPrior = 2
+
+type popularity struct {
+  impressions float64
+  selections  float64
+  updated     time.Time
+}
+
+func (p *popularity) decay(now time.Time, halfLife time.Duration) {
+  if now.After(p.updated) {
+    factor := math.Exp2(-now.Sub(p.updated).Seconds() / halfLife.Seconds())
+    p.impressions *= factor
+    p.selections *= factor
+    p.updated = now
+  }
+}
+
+// selectionRate is the smoothed share of the impressions that ended in a selection.
+func (p *popularity) selectionRate() float64 {
+  return (p.selections + smoothingPrior/2) / (p.impressions + smoothingPrior)
+}
+
+type prefixItem struct {
+  prefix string
+  itemId string
+}
+
+// Aggregator keeps the time-decayed impressions and selections of every item, overall and per prefix,
+// and boosts the item weights by how much their selection rate differs from the neutral one.
+type Aggregator struct {
+  HalfLife     time.Duration
+  ItemFactor   float64
+  PrefixFactor float64
+  // MaxPrefixItems bounds the number of the tracked prefix and item pairs, as the prefixes come from the clients.
+  // At the limit the new pairs are not tracked until the pruning forgets some, 0 means no limit.
+  MaxPrefixItems int
+
+  mutex       sync.Mutex
+  items       map[string]*popularity
+  prefixItems map[prefixItem]*popularity
+}
+
+func NewAggregator(halfLife time.Duration, itemFactor, prefixFactor float64) *Aggregator {
+  return &Aggregator{
+    HalfLife:     halfLife,
+    ItemFactor:   itemFactor,
+    PrefixFactor: prefixFactor,
+    items:        map[string]*popularity{},
+    prefixItems:  map[prefixItem]*popularity{},
+  }
+}
+
+// examinationProbability discounts the impressions at the bottom of the list, which users often do not read.
+func examinationProbability(position int) float64 {
+  if position < 0 {
+    position = 0
+  }
+  return 1 / math.Log2(float64(position)+2)
+}
+
+func (a *Aggregator) record(p *popularity, position int, selected bool, now time.Time) {
+  p.decay(now, a.HalfLife)
+  if selected {
+    p.impressions++
+    p.selections++
+  } else {
+    p.impressions += examinationProbability(position)
+  }
+}
+
+// Record accounts an item shown at the position for the normalized prefix, selected or not.
+func (a *Aggregator) Record(prefix, itemId string, position int, selected bool, now time.Time) {
+  a.mutex.Lock()
+  defer a.mutex.Unlock()
+  item, ok := a.items[itemId]
+  if !ok {
+    item = &popularity{updated: now}
+    a.items[itemId] = item
+  }
+  a.record(item, position, selected, now)
+
+  key := prefixItem{prefix: prefix, itemId: itemId}
+  itemForPrefix, ok := a.prefixItems[key]
+  if !ok {
+    if a.MaxPrefixItems > 0 && len(a.prefixItems) >= a.MaxPrefixItems {
+      return
+    }
+    itemForPrefix = &popularity{updated: now}
+    a.prefixItems[key] = itemForPrefix
+  }
+  a.record(itemForPrefix, position, selected, now)
+}
+
+func boost(p *popularity, factor float64, now time.Time, halfLife time.Duration) float64 {
+  if p == nil {
+    return 1
+  }
+  p.decay(now, halfLife)
+  return 1 + factor*(2*p.selectionRate()-1)
+}
+
+// ItemBoost is the weight multiplier of the item regardless of the prefix.
+func (a *Aggregator) ItemBoost(itemId string, now time.Time) float64 {
+  a.mutex.Lock()
+  defer a.mutex.Unlock()
+  return boost(a.items[itemId], a.ItemFactor, now, a.HalfLife)
+}
+
+// Boost multiplies the suggestion weights by the item and the prefix boosts and reorders the suggestions.
+func (a *Aggregator) Boost(normalizedPart string, suggestions []*suggest.SuggestAnswerItem) []*suggest.SuggestAnswerItem {
+  now := time.Now()
+  a.mutex.Lock()
+  for _, item := range suggestions {
+    id := item.Id()
+    multiplier := boost(a.items[id], a.ItemFactor, now, a.HalfLife) *
+      boost(a.prefixItems[prefixItem{prefix: normalizedPart, itemId: id}], a.PrefixFactor, now, a.HalfLife)
+    item.Weight = float32(float64(item.Weight) * multiplier)
+  }
+  a.mutex.Unlock()
+  sort.SliceStable(suggestions, func(i, j int) bool {
+    return suggestions[i].Weight > suggestions[j].Weight
+  })
+  return suggestions
+}
+
+// Prune forgets the items which impressions decayed below the threshold.
+func (a *Aggregator) Prune(threshold float64) {
+  now := time.Now()
+  a.mutex.Lock()
+  defer a.mutex.Unlock()
+  for id, p := range a.items {
+    if p.decay(now, a.HalfLife); p.impressions < threshold {
+      delete(a.items, id)
+    }
+  }
+  for key, p := range a.prefixItems {
+    if p.decay(now, a.HalfLife); p.impressions < threshold {
+      delete(a.prefixItems, key)
+    }
+  }
+}
+
+func (a *Aggregator) RunPruning(interval time.Duration, threshold float64) {
+  ticker := time.NewTicker(interval)
+  defer ticker.Stop()
+  for range ticker.C {
+    a.Prune(threshold)
+  }
+}
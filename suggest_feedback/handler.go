@@ -0,0 +1,76 @@
+package suggest_feedback
+
+import (
+  "encoding/json"
+  "fmt"
+  "log"
+  "main/network"
+  "main/suggest"
+  "net/http"
+  "strconv"
+  "time"
+)
+
+type FeedbackHandler struct {
+  Aggregator *Aggregator
+  Handler    *suggest.Handler
+}
+
+// HandleFeedbackRequest records an item shown for the prefix at the zero-based position and whether it was
+// selected. The parameters may be passed in the query or as a form of the POST request.
+func (fh *FeedbackHandler) HandleFeedbackRequest(w http.ResponseWriter, r *http.Request) {
+  if !network.RequirePost(w, r) {
+    return
+  }
+  text := r.FormValue("item")
+  if text == "" {
+    network.ReportBadRequest(w, "please specify the item parameter")
+    return
+  }
+  position := 0
+  if value := r.FormValue("position"); value != "" {
+    var err error
+    if position, err = strconv.Atoi(value); err != nil || position < 0 {
+      network.ReportBadRequest(w, fmt.Sprintf("cannot interpret %q as position", value))
+      return
+    }
+  }
+  selected := false
+  if value := r.FormValue("selected"); value != "" {
+    var err error
+    if selected, err = strconv.ParseBool(value); err != nil {
+      network.ReportBadRequest(w, fmt.Sprintf("cannot interpret %q as bool", value))
+      return
+    }
+  }
+  item := fh.Handler.GetItem(text)
+  if item == nil {
+    network.ReportBadRequest(w, fmt.Sprintf("unknown item %q", text))
+    return
+  }
+  _, normalizedPrefix := fh.Handler.NormalizePart(r.FormValue("prefix"))
+  fh.Aggregator.Record(normalizedPrefix, suggest.ItemId(item), position, selected, time.Now())
+  network.ReportSuccessMessage(w, "OK")
+}
+
+// HandleExportRequest writes the index items with the feedback applied to the weights in the build input
+// format, so that the next build picks the popularity up. The stored-only data is written too, so it is
+// to be served on the admin port only.
+func (fh *FeedbackHandler) HandleExportRequest(w http.ResponseWriter, _ *http.Request) {
+  network.WriteCORSHeaders(w)
+  w.Header().Set("Content-Type", "text/tab-separated-values; charset=utf-8")
+  w.WriteHeader(http.StatusOK)
+  now := time.Now()
+  for _, item := range fh.Handler.Suggest.Items {
+    data, err := json.Marshal(suggest.FullItemData(item))
+    if err != nil {
+      log.Printf("cannot export the data of %q: %v", item.OriginalText, err)
+      continue
+    }
+    weight := float64(item.Weight) * fh.Aggregator.ItemBoost(suggest.ItemId(item), now)
+    if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", item.OriginalText, strconv.FormatFloat(weight, 'f', -1, 32), data); err != nil {
+      log.Printf("cannot write the export: %v", err)
+      return
+    }
+  }
+}
@@ -0,0 +1,142 @@
+package suggest_grpc
+
+import (
+  "context"
+  "fmt"
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/metadata"
+  "google.golang.org/grpc/status"
+  "log"
+  stpb "main/proto/suggest/suggest_trie"
+  "main/suggest"
+  "strconv"
+  "time"
+)
+
+type Server struct {
+  stpb.UnimplementedSuggestServiceServer
+  Handler *suggest.Handler
+  // Ready tells whether the daemon takes requests, the same way its /health does, false while it starts or drains.
+  // The handler health is used when not set.
+  Ready func() bool
+}
+
+func NewServer(handler *suggest.Handler) *Server {
+  return &Server{
+    Handler: handler,
+  }
+}
+
+func (s *Server) Register(grpcServer *grpc.Server) {
+  stpb.RegisterSuggestServiceServer(grpcServer, s)
+}
+
+func (s *Server) setSuggestVersionHeader(ctx context.Context) {
+  header := metadata.Pairs("suggest-version", strconv.FormatUint(s.Handler.Suggest.Version, 10))
+  if err := grpc.SetHeader(ctx, header); err != nil {
+    log.Printf("cannot set the suggest version header: %v", err)
+  }
+}
+
+// clientId identifies the client application by the x-client-id metadata.
+func clientId(ctx context.Context) string {
+  if md, ok := metadata.FromIncomingContext(ctx); ok {
+    if values := md.Get("x-client-id"); len(values) > 0 {
+      return values[0]
+    }
+  }
+  return ""
+}
+
+func (s *Server) getSuggest(ctx context.Context, request *stpb.SuggestRequest) (*stpb.PaginatedSuggestResponse, error) {
+  start := time.Now()
+  invalidArgument := func(message string) error {
+    s.Handler.ObserveBadRequest("grpc")
+    return status.Error(codes.InvalidArgument, message)
+  }
+  if request.Count < 0 || request.Page < 0 {
+    return nil, invalidArgument("count and page must not be negative")
+  }
+  filters, err := suggest.ParseFilters(request.Filters)
+  if err != nil {
+    return nil, invalidArgument(err.Error())
+  }
+  projection, err := suggest.ParseProjection(request.Fields)
+  if err != nil {
+    return nil, invalidArgument(err.Error())
+  }
+  pagingParameters := &suggest.PagingParameters{
+    Count:        int(request.Count),
+    Page:         int(request.Page),
+    PaginationOn: request.PaginationOn,
+  }
+  if request.Cursor != "" {
+    if pagingParameters.Cursor, err = suggest.ParseCursor(request.Cursor); err != nil {
+      return nil, invalidArgument(err.Error())
+    }
+    pagingParameters.PaginationOn = true
+  }
+  q := &suggest.SuggestQuery{
+    Part:           request.Part,
+    Classes:        request.Classes,
+    ExcludeClasses: request.ExcludeClasses,
+    Filters:        filters,
+    Deep:           request.Deep,
+  }
+  s.Handler.SetPaging(q, pagingParameters)
+  suggestions := s.Handler.GetSuggest(q)
+  s.Handler.ObserveQuery("grpc", q, len(suggestions), start)
+  s.Handler.LogQuery("grpc", clientId(ctx), q, pagingParameters, len(suggestions), start)
+  paginatedResponse := pagingParameters.Paginate(suggestions)
+  projection.Apply(paginatedResponse.Suggestions)
+  response, err := paginatedResponse.ToProto()
+  if err != nil {
+    return nil, status.Error(codes.Internal, err.Error())
+  }
+  return response, nil
+}
+
+func (s *Server) Suggest(ctx context.Context, request *stpb.SuggestRequest) (*stpb.PaginatedSuggestResponse, error) {
+  s.setSuggestVersionHeader(ctx)
+  return s.getSuggest(ctx, request)
+}
+
+func (s *Server) BatchSuggest(ctx context.Context, request *stpb.BatchSuggestRequest) (*stpb.BatchSuggestResponse, error) {
+  s.setSuggestVersionHeader(ctx)
+  response := &stpb.BatchSuggestResponse{}
+  for idx, suggestRequest := range request.Requests {
+    suggestResponse, err := s.getSuggest(ctx, suggestRequest)
+    if err != nil {
+      return nil, status.Error(status.Code(err), fmt.Sprintf("request #%d: %s", idx, status.Convert(err).Message()))
+    }
+    response.Responses = append(response.Responses, suggestResponse)
+  }
+  return response, nil
+}
+
+func (s *Server) Health(_ context.Context, _ *stpb.HealthRequest) (*stpb.HealthResponse, error) {
+  ready := false
+  if s.Ready != nil {
+    ready = s.Ready()
+  } else {
+    ready, _ = s.Handler.Health()
+  }
+  healthStatus := "OK"
+  if !ready {
+    healthStatus = "NOT_SERVING"
+  }
+  return &stpb.HealthResponse{
+    Status:  healthStatus,
+    Version: s.Handler.Suggest.Version,
+  }, nil
+}
+
+func (s *Server) GetItem(ctx context.Context, request *stpb.GetItemRequest) (*stpb.Item, error) {
+  s.setSuggestVersionHeader(ctx)
+  item := s.Handler.GetItem(request.Text)
+  if item == nil {
+    return nil, status.Errorf(codes.NotFound, "no item found for %q", request.Text)
+  }
+  return item, nil
+}
@@ -0,0 +1,31 @@
+package suggest_merger
+
+import (
+  "main/access_log"
+  "main/suggest"
+  "main/tools"
+  "net/http"
+  "time"
+)
+
+// logQuery writes the query to the access log when it is sampled. The merger does not know the normalization
+// of the shards, so the prefix is normalized the default way.
+func (h *Handler) logQuery(r *http.Request, pagingParameters *suggest.PagingParameters, resultsCount int, version uint64, start time.Time) {
+  if !h.AccessLog.Sample() {
+    return
+  }
+  query := r.URL.Query()
+  h.AccessLog.Write(&access_log.Entry{
+    Time:           start.UTC().Format(time.RFC3339Nano),
+    Endpoint:       "merger",
+    Prefix:         tools.NormalizeString(query.Get("part"), tools.GetPolicy()),
+    Classes:        query["class"],
+    ExcludeClasses: query["exclude-class"],
+    Filters:        query["filter"],
+    Count:          pagingParameters.NeededCount(),
+    ResultsCount:   resultsCount,
+    LatencyMs:      float64(time.Since(start).Microseconds()) / 1000,
+    Version:        version,
+    ClientId:       access_log.ClientId(r),
+  })
+}
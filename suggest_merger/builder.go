@@ -1,160 +1,136 @@
 package suggest_merger
 
 import (
-  "bufio"
   "fmt"
   "github.com/microcosm-cc/bluemonday"
-  "google.golang.org/protobuf/proto"
-  "io"
+  "golang.org/x/sync/errgroup"
   "io/ioutil"
   "log"
   "main/suggest"
   "main/tools"
-  "os"
   "sort"
-  "strings"
   "time"
 )
 
-type characterStat struct {
-  Count      int
-  StartIndex int
-  EndIndex   int
-}
-
-func DoBuildShardedSuggest(inputFilePath string, suggestDataPath string, maxItemsPerPrefix int, suffixFactor float64, buildWithoutSuffixes bool, countOutputFiles int) {
-  if !isFileSorted(inputFilePath) {
-    log.Fatalf("file is not sorted, use linux command 'sort', example: sort --parallel 4 -o suggest.data suggest.data")
-  }
-
-  charactersStat, err := getCharacterStatByPrefixes(inputFilePath)
+func DoBuildShardedSuggest(
+  inputFilePath string,
+  layout *suggest.OutputLayout,
+  parameters *suggest.BuildParameters,
+  shardingOptions *ShardingOptions,
+  parallelism int,
+) {
+  strategy, err := NewShardingStrategy(shardingOptions)
   if err != nil {
     log.Fatalln(err)
   }
 
-  parts, err := getDistributionByParts(charactersStat, countOutputFiles)
+  buckets, err := partitionItems(inputFilePath, strategy, tools.GetPolicy())
   if err != nil {
     log.Fatalln(err)
   }
 
-  policy := tools.GetPolicy()
-  suggestVersion := uint64(time.Now().Unix())
-  for shardNumber, characters := range parts {
-    var items []*suggest.Item
-
-    for _, c := range characters {
-      itemsPart, err := loadItemsByPart(inputFilePath, charactersStat[c].StartIndex, charactersStat[c].EndIndex, policy)
-      if err != nil {
-        log.Fatalln(err)
-      }
-      items = append(items, itemsPart...)
-    }
-
-    suggestData, err := suggest.BuildSuggestData(items, maxItemsPerPrefix, float32(suffixFactor), buildWithoutSuffixes)
-    if err != nil {
-      log.Fatalln(err)
-    }
-    suggest.SetVersion(suggestData, suggestVersion)
-
-    log.Printf("marshalling suggest as proto")
-    b, err := proto.Marshal(suggestData)
-    if err != nil {
-      log.Fatalln(err)
-    }
+  keysCount := map[string]int{}
+  for key, items := range buckets {
+    keysCount[key] = len(items)
+  }
+  plan := strategy.Plan(keysCount, shardingOptions.CountOutputFiles)
 
-    suggestDataPathPart := strings.ReplaceAll(suggestDataPath, ".", fmt.Sprintf("_%d.", shardNumber))
+  keys := make([]string, 0, len(buckets))
+  for key := range buckets {
+    keys = append(keys, key)
+  }
+  sort.Strings(keys)
+  shardsItems := make([][]*suggest.Item, len(plan.ShardsKeys))
+  for _, key := range keys {
+    shardNumber := plan.ShardOf(key)
+    shardsItems[shardNumber] = append(shardsItems[shardNumber], buckets[key]...)
+  }
 
-    log.Printf("writing the resulting proto suggest data to %s with prefixes %v, items count %d, version %d", suggestDataPathPart, characters, len(items), suggestData.Version)
-    if err := ioutil.WriteFile(suggestDataPathPart, b, 0644); err != nil {
-      log.Fatalln(err)
-    }
+  if err := layout.Prepare(); err != nil {
+    log.Fatalln(err)
   }
-  return
-}
 
-func isFileSorted(inputFilePath string) bool {
-  file, err := os.Open(inputFilePath)
-  if err != nil {
-    return false
+  suggestVersion := uint64(time.Now().Unix())
+  manifest := suggest.NewManifest(suggestVersion, parameters)
+  manifest.Sharding = &suggest.ShardingInfo{
+    Strategy:   plan.Strategy,
+    Parameters: plan.Parameters,
   }
-  defer file.Close()
+  manifest.Files = make([]*suggest.ManifestFile, len(plan.ShardsKeys))
 
-  setOfFirstCharacters := make([]string, 0)
+  g := &errgroup.Group{}
+  if parallelism > 0 {
+    g.SetLimit(parallelism)
+  }
+  for shardNumber, keys := range plan.ShardsKeys {
+    shardNumber, keys := shardNumber, keys // https://golang.org/doc/faq#closures_and_goroutines
 
-  scanner := bufio.NewScanner(file)
-  for scanner.Scan() {
-    line := strings.TrimSpace(scanner.Text())
+    g.Go(func() error {
+      manifestFile, err := buildShard(shardsItems[shardNumber], layout, suggest.ShardDataPath(layout.DataPath, shardNumber), parameters, suggestVersion)
+      if err != nil {
+        return fmt.Errorf("cannot build shard #%d: %v", shardNumber, err)
+      }
+      log.Printf("built shard #%d with %s keys %v", shardNumber, plan.Strategy, keys)
+      manifestFile.ShardKeys = keys
+      manifest.Files[shardNumber] = manifestFile
+      return nil
+    })
+  }
+  if err := g.Wait(); err != nil {
+    log.Fatalln(err)
+  }
 
-    if len(line) == 0 {
-      continue
-    }
+  for shardNumber, manifestFile := range manifest.Files {
+    log.Printf("shard #%d: %s, items count %d, nodes count %d, size %d bytes", shardNumber, manifestFile.Path, manifestFile.ItemsCount, manifestFile.NodesCount, manifestFile.Size)
+  }
 
-    setOfFirstCharacters = append(setOfFirstCharacters, strings.ToLower(line[0:1]))
+  log.Printf("writing the build manifest to %s", layout.ManifestPath)
+  if err := manifest.Write(layout.ManifestPath); err != nil {
+    log.Fatalln(err)
   }
+}
 
-  return sort.SliceIsSorted(setOfFirstCharacters, func(i, j int) bool {
-    return setOfFirstCharacters[i] < setOfFirstCharacters[j]
+// partitionItems reads the input once, grouping the items by their sharding key.
+func partitionItems(inputFilePath string, strategy ShardingStrategy, policy *bluemonday.Policy) (map[string][]*suggest.Item, error) {
+  buckets := map[string][]*suggest.Item{}
+  err := suggest.ScanItems(inputFilePath, policy, func(item *suggest.Item) error {
+    key := strategy.Key(item)
+    buckets[key] = append(buckets[key], item)
+    return nil
   })
+  if err != nil {
+    return nil, err
+  }
+  return buckets, nil
 }
 
-func getCharacterStatByPrefixes(inputFilePath string) (map[string]*characterStat, error) {
-  symbolsMapCounter := map[string]*characterStat{}
-
-  file, err := os.Open(inputFilePath)
+func buildShard(
+  items []*suggest.Item,
+  layout *suggest.OutputLayout,
+  suggestDataPathPart string,
+  parameters *suggest.BuildParameters,
+  suggestVersion uint64,
+) (*suggest.ManifestFile, error) {
+  suggestData, err := suggest.BuildSuggestData(items, parameters.MaxItemsPerPrefix, float32(parameters.SuffixFactor), parameters.BuildWithoutSuffixes)
   if err != nil {
     return nil, err
   }
-  defer file.Close()
-
-  scanner := bufio.NewScanner(file)
-  lineNumber := 0
-  currentCharacterForStat := ""
-  currentStartPos, currentEndPos, currentCounter := 0, 0, 0
-
-  for scanner.Scan() {
-    line := strings.TrimSpace(scanner.Text())
-
-    if len(line) == 0 {
-      continue
-    }
-
-    firstCharacter := strings.ToLower(line[0:1])
-
-    if firstCharacter != currentCharacterForStat {
-      if currentCharacterForStat != "" {
-        currentEndPos += currentCounter - 1
-        symbolsMapCounter[currentCharacterForStat] = &characterStat{
-          Count:      currentCounter,
-          StartIndex: currentStartPos,
-          EndIndex:   currentEndPos,
-        }
-        currentStartPos = currentEndPos + 1
-        currentEndPos = currentStartPos
-        currentCounter = 0
-      }
-      currentCharacterForStat = firstCharacter
-    }
-
-    currentEndPos += len(line)
-    currentCounter += 1
-
-    lineNumber++
-    if lineNumber%100000 == 0 {
-      log.Printf("read %d lines", lineNumber)
-    }
+  suggest.SetVersion(suggestData, suggestVersion)
+  suggest.SetFacets(suggestData, parameters.Facets)
+  if err := suggest.SplitStoredData(suggestData, parameters.StoredOnlyFields); err != nil {
+    return nil, err
   }
 
-  // processing for last characters
-  if currentCharacterForStat != "" {
-    currentEndPos += currentCounter - 1
-    symbolsMapCounter[currentCharacterForStat] = &characterStat{
-      Count:      currentCounter,
-      StartIndex: currentStartPos,
-      EndIndex:   currentEndPos,
-    }
+  b, err := suggest.MarshalSuggest(suggestData)
+  if err != nil {
+    return nil, err
   }
 
-  return symbolsMapCounter, nil
+  log.Printf("writing the resulting proto suggest data to %s, items count %d, version %d", suggestDataPathPart, len(items), suggestData.Version)
+  if err := ioutil.WriteFile(suggestDataPathPart, b, 0644); err != nil {
+    return nil, err
+  }
+  return suggest.NewManifestFile(layout.ManifestPath, suggestDataPathPart, b, suggestData)
 }
 
 func getIndexOfMin(items []float64) int {
@@ -169,16 +145,17 @@ func getIndexOfMin(items []float64) int {
   return minIdx
 }
 
-func getDistributionByParts(charactersStat map[string]*characterStat, countParts int) (map[int][]string, error) {
-  characters := make([]string, 0, len(charactersStat))
+func getDistributionByParts(keysCount map[string]int, countParts int) map[int][]string {
+  keys := make([]string, 0, len(keysCount))
   sumWeights := 0
 
-  for c, value := range charactersStat {
-    characters = append(characters, c)
-    sumWeights += value.Count
+  for key, count := range keysCount {
+    keys = append(keys, key)
+    sumWeights += count
   }
-  sort.SliceStable(characters, func(i, j int) bool {
-    return charactersStat[characters[i]].Count > charactersStat[characters[j]].Count
+  sort.Strings(keys)
+  sort.SliceStable(keys, func(i, j int) bool {
+    return keysCount[keys[i]] > keysCount[keys[j]]
   })
 
   // the first estimate of the maximum part volume is the total volume divided to all parts
@@ -189,8 +166,8 @@ func getDistributionByParts(charactersStat map[string]*characterStat, countParts
   parts := map[int][]string{}
   restWeightsSum := sumWeights
 
-  for _, c := range characters {
-    weight := charactersStat[c].Count
+  for _, key := range keys {
+    weight := keysCount[key]
 
     // put next value in part with lowest weight sum
     lowestPartIndex := getIndexOfMin(weightsParts)
@@ -200,7 +177,7 @@ func getDistributionByParts(charactersStat map[string]*characterStat, countParts
     foundPart := false
     for !foundPart {
       if newWeightSum <= maxSize {
-        parts[lowestPartIndex] = append(parts[lowestPartIndex], c)
+        parts[lowestPartIndex] = append(parts[lowestPartIndex], key)
         weightsParts[lowestPartIndex] = newWeightSum
         restWeightsSum -= weight
         foundPart = true
@@ -214,43 +191,5 @@ func getDistributionByParts(charactersStat map[string]*characterStat, countParts
       }
     }
   }
-  return parts, nil
-}
-
-func loadItemsByPart(inputFilePath string, startIndex int, endIndex int, policy *bluemonday.Policy) ([]*suggest.Item, error) {
-  file, err := os.Open(inputFilePath)
-  if err != nil {
-    return nil, err
-  }
-  var items []*suggest.Item
-
-  if _, err := file.Seek(int64(startIndex), io.SeekStart); err != nil {
-    log.Fatal(err)
-  }
-  scanner := bufio.NewScanner(file)
-
-  currentLen := startIndex
-  lineNumber := 0
-  for scanner.Scan() {
-    line := strings.TrimSpace(scanner.Text())
-    if len(line) == 0 {
-      continue
-    }
-    item, err := suggest.NewItem(line, policy)
-    if err != nil {
-      return nil, fmt.Errorf("error processing line #%d: %v", lineNumber, err)
-    }
-    items = append(items, item)
-    lineNumber++
-    if lineNumber%100000 == 0 {
-      log.Printf("read %d lines", lineNumber)
-    }
-
-    currentLen += len(line) + 1
-    if currentLen >= endIndex {
-      break
-    }
-
-  }
-  return items, nil
+  return parts
 }
@@ -1,13 +1,38 @@
 package suggest_merger
 
 import (
+  "bufio"
   "encoding/json"
+  "fmt"
   "io/ioutil"
+  "main/suggest"
+  "net/url"
   "os"
+  "path/filepath"
+  "sort"
+  "strconv"
+  "strings"
 )
 
 type Config struct {
-  SuggestShardsUrls []string `json:"suggest_shards_urls"`
+  SuggestShardsUrls  []string `json:"suggest_shards_urls"`
+  ShardsDiscoveryDir string   `json:"shards_discovery_dir"`
+}
+
+// ShardNumberPlaceholder is replaced by the shard number in the url template of the shards serving a manifest.
+const ShardNumberPlaceholder = "{shard}"
+
+// NewManifestConfig makes the config of the remote shards serving the files of a sharded build, one shard
+// per file in the manifest order, e.g. started with serve --manifest --shard N.
+func NewManifestConfig(manifest *suggest.Manifest, shardUrlTemplate string) (*Config, error) {
+  if !strings.Contains(shardUrlTemplate, ShardNumberPlaceholder) {
+    return nil, fmt.Errorf("the shard url template %q has no %s placeholder", shardUrlTemplate, ShardNumberPlaceholder)
+  }
+  config := &Config{}
+  for i := range manifest.Files {
+    config.SuggestShardsUrls = append(config.SuggestShardsUrls, strings.ReplaceAll(shardUrlTemplate, ShardNumberPlaceholder, strconv.Itoa(i)))
+  }
+  return config, nil
 }
 
 func ReadConfig(configPath string) (*Config, error) {
@@ -17,10 +42,87 @@ func ReadConfig(configPath string) (*Config, error) {
   }
   defer jsonFile.Close()
 
-  byteValue, _ := ioutil.ReadAll(jsonFile)
+  byteValue, err := ioutil.ReadAll(jsonFile)
+  if err != nil {
+    return nil, err
+  }
   config := &Config{}
   if err = json.Unmarshal(byteValue, config); err != nil {
     return nil, err
   }
   return config, nil
 }
+
+// ShardsUrls returns the statically configured shard urls followed by the ones found in the discovery directory.
+// Every regular file of the directory lists shard urls one per line, empty lines and lines starting with # are skipped.
+func (c *Config) ShardsUrls() ([]string, error) {
+  shardsUrls := append([]string{}, c.SuggestShardsUrls...)
+  if c.ShardsDiscoveryDir == "" {
+    return shardsUrls, nil
+  }
+  entries, err := ioutil.ReadDir(c.ShardsDiscoveryDir)
+  if err != nil {
+    return nil, fmt.Errorf("cannot read shards discovery dir: %v", err)
+  }
+  sort.Slice(entries, func(i, j int) bool {
+    return entries[i].Name() < entries[j].Name()
+  })
+  for _, entry := range entries {
+    if !entry.Mode().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+      continue
+    }
+    discoveredUrls, err := readEndpointsFile(filepath.Join(c.ShardsDiscoveryDir, entry.Name()))
+    if err != nil {
+      return nil, err
+    }
+    shardsUrls = append(shardsUrls, discoveredUrls...)
+  }
+  return shardsUrls, nil
+}
+
+func readEndpointsFile(endpointsFilePath string) ([]string, error) {
+  file, err := os.Open(endpointsFilePath)
+  if err != nil {
+    return nil, err
+  }
+  defer file.Close()
+
+  var endpoints []string
+  scanner := bufio.NewScanner(file)
+  for scanner.Scan() {
+    line := strings.TrimSpace(scanner.Text())
+    if len(line) == 0 || strings.HasPrefix(line, "#") {
+      continue
+    }
+    endpoints = append(endpoints, line)
+  }
+  if err := scanner.Err(); err != nil {
+    return nil, fmt.Errorf("cannot read endpoints file %s: %v", endpointsFilePath, err)
+  }
+  return endpoints, nil
+}
+
+func (c *Config) Validate() error {
+  shardsUrls, err := c.ShardsUrls()
+  if err != nil {
+    return err
+  }
+  if len(shardsUrls) == 0 {
+    return fmt.Errorf("no suggest shards urls configured")
+  }
+  seenUrls := map[string]bool{}
+  for _, shardUrl := range shardsUrls {
+    parsedUrl, err := url.Parse(shardUrl)
+    if err != nil {
+      return fmt.Errorf("invalid shard url %q: %v", shardUrl, err)
+    }
+    if (parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") || parsedUrl.Host == "" {
+      return fmt.Errorf("invalid shard url %q: an absolute http(s) url expected", shardUrl)
+    }
+    if seenUrls[shardUrl] {
+      return fmt.Errorf("duplicate shard url %q", shardUrl)
+    }
+    seenUrls[shardUrl] = true
+  }
+  return nil
+}
@@ -0,0 +1,96 @@
+package suggest_merger
+
+import (
+  "fmt"
+  "io/ioutil"
+  "log"
+  "os"
+  "os/signal"
+  "strings"
+  "syscall"
+  "time"
+)
+
+// ConfigWatcher reloads the merger config when the config file or the shards discovery dir changes
+// and on SIGHUP.
+type ConfigWatcher struct {
+  ConfigPath string
+  Handler    *Handler
+  Interval   time.Duration
+
+  fingerprint string
+}
+
+func NewConfigWatcher(configPath string, handler *Handler, interval time.Duration) *ConfigWatcher {
+  cw := &ConfigWatcher{
+    ConfigPath: configPath,
+    Handler:    handler,
+    Interval:   interval,
+  }
+  cw.fingerprint = cw.getFingerprint()
+  return cw
+}
+
+func fileFingerprint(path string) string {
+  info, err := os.Stat(path)
+  if err != nil {
+    return path + ":missing"
+  }
+  return fmt.Sprintf("%s:%d:%d", path, info.Size(), info.ModTime().UnixNano())
+}
+
+func (cw *ConfigWatcher) getFingerprint() string {
+  parts := []string{fileFingerprint(cw.ConfigPath)}
+  config, err := ReadConfig(cw.ConfigPath)
+  if err != nil || config.ShardsDiscoveryDir == "" {
+    return strings.Join(parts, ";")
+  }
+  parts = append(parts, fileFingerprint(config.ShardsDiscoveryDir))
+  entries, err := ioutil.ReadDir(config.ShardsDiscoveryDir)
+  if err == nil {
+    for _, entry := range entries {
+      parts = append(parts, fmt.Sprintf("%s:%d:%d", entry.Name(), entry.Size(), entry.ModTime().UnixNano()))
+    }
+  }
+  return strings.Join(parts, ";")
+}
+
+func (cw *ConfigWatcher) reload(reason string) {
+  config, err := ReadConfig(cw.ConfigPath)
+  if err != nil {
+    log.Printf("cannot reload merger config on %s: %v", reason, err)
+    return
+  }
+  if err := cw.Handler.Reload(config); err != nil {
+    log.Printf("rejected merger config on %s, keeping the previous one: %v", reason, err)
+    return
+  }
+  log.Printf("reloaded merger config on %s: %d shards", reason, len(cw.Handler.getShards()))
+}
+
+func (cw *ConfigWatcher) Run() {
+  reloadSignal := make(chan os.Signal, 1)
+  signal.Notify(reloadSignal, syscall.SIGHUP)
+
+  var tick <-chan time.Time
+  if cw.Interval > 0 {
+    ticker := time.NewTicker(cw.Interval)
+    defer ticker.Stop()
+    tick = ticker.C
+  }
+
+  for {
+    select {
+    case <-reloadSignal:
+      cw.fingerprint = cw.getFingerprint()
+      cw.reload("SIGHUP")
+    case <-tick:
+      fingerprint := cw.getFingerprint()
+      if fingerprint == cw.fingerprint {
+        continue
+      }
+      cw.fingerprint = fingerprint
+      cw.reload("config change")
+    }
+  }
+}
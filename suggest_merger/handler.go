@@ -1,49 +1,106 @@
 package suggest_merger
 
 import (
-  "context"
-  "encoding/json"
   "fmt"
   "github.com/hashicorp/go-retryablehttp"
-  "golang.org/x/sync/errgroup"
   "io/ioutil"
   "log"
+  "main/access_log"
   "main/network"
   "main/suggest"
+  "math"
   "net/http"
   "net/url"
   "strconv"
+  "sync"
   "time"
 )
 
 type Handler struct {
-  Config            *Config
-  SuggestClient     *SuggestClient
-  SuggestShardsUrls []url.URL
+  Config        *Config
+  SuggestClient *SuggestClient
+  Shards        []Shard
+  AccessLog     *access_log.Logger
+  // MinHealthyShards is the share of the healthy shards the merger needs to be ready.
+  MinHealthyShards float64
+
+  mutex        sync.RWMutex
+  shardsHealth []*ShardHealth
+  classes      sync.Map
 }
 
 func NewHandler(config *Config) (*Handler, error) {
   h := &Handler{
-    SuggestClient: NewSuggestClient(),
-    Config:        config,
+    SuggestClient:    NewSuggestClient(),
+    MinHealthyShards: DefaultMinHealthyShards,
   }
-  if err := h.initSuggestShardsUrls(); err != nil {
+  if err := h.Reload(config); err != nil {
     return nil, err
   }
   return h, nil
 }
 
-func (h *Handler) initSuggestShardsUrls() error {
-  for _, suggestShardUrl := range h.Config.SuggestShardsUrls {
+func NewLocalHandler(shards []Shard) *Handler {
+  h := &Handler{
+    Shards:           shards,
+    MinHealthyShards: DefaultMinHealthyShards,
+  }
+  h.setShardsHealth(checkShards(shards))
+  return h
+}
+
+func (h *Handler) newRemoteShards(config *Config) ([]Shard, error) {
+  shardsUrls, err := config.ShardsUrls()
+  if err != nil {
+    return nil, err
+  }
+  var shards []Shard
+  for _, suggestShardUrl := range shardsUrls {
     shardUrl, err := url.Parse(suggestShardUrl)
     if err != nil {
-      return err
+      return nil, err
     }
-    h.SuggestShardsUrls = append(h.SuggestShardsUrls, *shardUrl)
+    shards = append(shards, &RemoteShard{
+      Url:           *shardUrl,
+      SuggestClient: h.SuggestClient,
+    })
+  }
+  return shards, nil
+}
+
+// Reload validates the config and atomically replaces the shards the handler fans out to;
+// the current config and shards stay in place if the new config is invalid. The new shards are
+// checked before the replacement, so that the readiness is known right away.
+func (h *Handler) Reload(config *Config) error {
+  if err := config.Validate(); err != nil {
+    return err
   }
+  shards, err := h.newRemoteShards(config)
+  if err != nil {
+    return err
+  }
+  health := checkShards(shards)
+  h.mutex.Lock()
+  defer h.mutex.Unlock()
+  h.Config = config
+  h.Shards = shards
+  h.setShardsHealth(health)
   return nil
 }
 
+func (h *Handler) getShards() []Shard {
+  h.mutex.RLock()
+  defer h.mutex.RUnlock()
+  return h.Shards
+}
+
+// getShardsHealth returns the shards along with their health at the last check, in the same order.
+func (h *Handler) getShardsHealth() ([]Shard, []*ShardHealth) {
+  h.mutex.RLock()
+  defer h.mutex.RUnlock()
+  return h.Shards, h.shardsHealth
+}
+
 type SuggestClient struct {
   httpClient *retryablehttp.Client
 }
@@ -54,6 +111,11 @@ func NewSuggestClient() *SuggestClient {
   retryableClient.RetryWaitMin = 10 * time.Millisecond
   retryableClient.RetryWaitMax = 1 * time.Second
   retryableClient.HTTPClient.Timeout = 10 * time.Second
+  retryableClient.RequestLogHook = func(_ retryablehttp.Logger, req *http.Request, attempt int) {
+    if attempt > 0 {
+      shardRetriesCount.With(req.URL.Host).Inc()
+    }
+  }
 
   return &SuggestClient{
     httpClient: retryableClient,
@@ -82,66 +144,115 @@ func getSuggestVersion(header http.Header) uint64 {
   return v
 }
 
-func (h *Handler) HandleMergerSuggestRequest(w http.ResponseWriter, r *http.Request) {
-  doRequests := func(ctx context.Context, query url.Values) (
-    []*suggest.PaginatedSuggestResponse,
-    []uint64,
-    error,
-  ) {
-    g, ctx := errgroup.WithContext(ctx)
-
-    results := make([]*suggest.PaginatedSuggestResponse, len(h.SuggestShardsUrls))
-    versions := make([]uint64, len(h.SuggestShardsUrls))
-
-    query.Add("api-version", "2")
-
-    for i, suggestShardUrl := range h.SuggestShardsUrls {
-      i, suggestShardUrl := i, suggestShardUrl // https://golang.org/doc/faq#closures_and_goroutines
+// shardsQuery asks every shard for its best items up to the end of the requested page,
+// the page itself is cut after the shard responses are merged. The fields are projected after the merge too,
+// since the merged page needs the item ids for its cursor.
+func shardsQuery(srcQuery url.Values, pagingParameters *suggest.PagingParameters) url.Values {
+  query := url.Values{}
+  for key, values := range srcQuery {
+    query[key] = append([]string{}, values...)
+  }
+  query.Set("api-version", "2")
+  query.Del("fields")
+  if pagingParameters.PaginationOn && pagingParameters.Count != 0 {
+    query.Set("page", "0")
+    query.Set("count", strconv.Itoa((pagingParameters.Page+1)*pagingParameters.Count))
+  }
+  return query
+}
 
-      g.Go(func() error {
-        suggestShardUrl.RawQuery = query.Encode()
+func mergeResponses(results []*suggest.PaginatedSuggestResponse, pagingParameters *suggest.PagingParameters) *suggest.PaginatedSuggestResponse {
+  suggestions := []*suggest.SuggestAnswerItem{}
+  totalItemsCount := 0
+  for _, result := range results {
+    if result == nil {
+      continue
+    }
+    suggestions = append(suggestions, result.Suggestions...)
+    totalItemsCount += result.TotalItemsCount
+  }
+  suggest.SortForPagination(suggestions)
 
-        _, result, headers, err := h.SuggestClient.Get(suggestShardUrl.String(), r.Header)
-        if err != nil {
-          return err
-        }
+  paginatedResp := pagingParameters.Paginate(suggestions)
+  if paginatedResp.NextCursor == "" && len(paginatedResp.Suggestions) > 0 {
+    for _, result := range results {
+      if result != nil && result.NextCursor != "" {
+        paginatedResp.NextCursor = suggest.NewCursor(paginatedResp.Suggestions[len(paginatedResp.Suggestions)-1]).String()
+        break
+      }
+    }
+  }
+  if pagingParameters.PaginationOn && totalItemsCount > paginatedResp.TotalItemsCount {
+    paginatedResp.TotalItemsCount = totalItemsCount
+    if pagingParameters.Count != 0 {
+      paginatedResp.TotalPagesCount = int(math.Ceil(float64(totalItemsCount) / float64(pagingParameters.Count)))
+    }
+  }
+  return paginatedResp
+}
 
-        versions[i] = getSuggestVersion(headers)
+func (h *Handler) HandleMergerSuggestRequest(w http.ResponseWriter, r *http.Request) {
+  start := time.Now()
+  // doRequests asks the shards which were healthy at the last check, the failed and the skipped shards are left
+  // out of the merge, so that the merger serves the partial results while it reports ready.
+  doRequests := func(query url.Values) ([]*suggest.PaginatedSuggestResponse, []uint64) {
+    shards, health := h.getShardsHealth()
+    results := make([]*suggest.PaginatedSuggestResponse, len(shards))
+    versions := make([]uint64, len(shards))
 
-        paginatedResponse := &suggest.PaginatedSuggestResponse{}
-        err = json.Unmarshal(result, paginatedResponse)
+    var wg sync.WaitGroup
+    for i, shard := range shards {
+      i, shard := i, shard // https://golang.org/doc/faq#closures_and_goroutines
+      if i < len(health) && health[i] != nil && !health[i].Healthy {
+        continue
+      }
+      wg.Add(1)
+      go func() {
+        defer wg.Done()
+        start := time.Now()
+        result, version, err := shard.GetSuggest(query, r.Header)
+        observeShardRequest(shard.Name(), err, start)
         if err != nil {
-          return err
+          log.Printf("shard %s is left out of the merge: %v", shard.Name(), err)
+          return
         }
-        results[i] = paginatedResponse
-
-        return nil
-      })
-    }
-    if err := g.Wait(); err != nil {
-      return nil, nil, err
+        results[i] = result
+        versions[i] = version
+      }()
     }
-    return results, versions, nil
+    wg.Wait()
+    return results, versions
   }
 
   srcQuery := r.URL.Query()
-  results, versions, err := doRequests(context.Background(), srcQuery)
+  projection, err := suggest.ParseProjection(srcQuery["fields"])
   if err != nil {
-    log.Println(err)
+    badRequestsCount.With().Inc()
+    network.ReportBadRequest(w, err.Error())
+    return
   }
+  pagingParameters := suggest.NewPagingParameters(srcQuery)
+  pagingParameters.Deep = srcQuery.Get("deep") == "1" || pagingParameters.Cursor != nil
+  results, versions := doRequests(shardsQuery(srcQuery, pagingParameters))
 
-  paginatedResp := &suggest.PaginatedSuggestResponse{
-    Suggestions: []*suggest.SuggestAnswerItem{},
+  paginatedResp := mergeResponses(results, pagingParameters)
+  resultsCount := len(paginatedResp.Suggestions)
+  if paginatedResp.TotalItemsCount > resultsCount {
+    resultsCount = paginatedResp.TotalItemsCount
   }
+  h.recordClasses(paginatedResp.Suggestions)
+  h.observeQuery(srcQuery, resultsCount, start)
+  projection.Apply(paginatedResp.Suggestions)
+
   var maxVersion uint64
-  for i, version := range versions {
-    if version > maxVersion && len(results[i].Suggestions) > 0 {
-      paginatedResp = results[i]
+  for _, version := range versions {
+    if version > maxVersion {
       maxVersion = version
     }
   }
+  w.Header().Add("Suggest-Version", strconv.FormatUint(maxVersion, 10))
+  h.logQuery(r, pagingParameters, resultsCount, maxVersion, start)
 
-  pagingParameters := suggest.NewPagingParameters(srcQuery)
   if pagingParameters.PaginationOn {
     network.ReportSuccessData(w, paginatedResp)
   } else {
@@ -150,5 +261,6 @@ func (h *Handler) HandleMergerSuggestRequest(w http.ResponseWriter, r *http.Requ
 }
 
 func (h *Handler) HandleMergerHealthRequest(w http.ResponseWriter, _ *http.Request) {
-  network.ReportSuccessMessage(w, "OK")
+  ready, _ := h.Health()
+  network.ReportHealth(w, ready)
 }
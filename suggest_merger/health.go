@@ -0,0 +1,145 @@
+package suggest_merger
+
+import (
+  "context"
+  "encoding/json"
+  "fmt"
+  "net/http"
+  "sync"
+  "time"
+)
+
+const healthCheckTimeout = 2 * time.Second
+
+// DefaultMinHealthyShards is the share of the healthy shards the merger needs to be ready.
+const DefaultMinHealthyShards = 0.5
+
+type ShardHealth struct {
+  Name      string   `json:"name"`
+  Healthy   bool     `json:"healthy"`
+  Error     string   `json:"error,omitempty"`
+  CheckedAt string   `json:"checked_at"`
+  Classes   []string `json:"-"`
+}
+
+type MergerHealth struct {
+  ShardsCount        int            `json:"shards_count"`
+  HealthyShardsCount int            `json:"healthy_shards_count"`
+  HealthyShards      float64        `json:"healthy_shards"`
+  MinHealthyShards   float64        `json:"min_healthy_shards"`
+  Shards             []*ShardHealth `json:"shards"`
+}
+
+// CheckHealth asks the readiness endpoint of the daemon serving the shard, without retries. The classes of the index
+// are read from the readiness details of the daemons serving a single index.
+func (sc *SuggestClient) CheckHealth(healthURL string) ([]string, error) {
+  ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+  defer cancel()
+  req, err := http.NewRequestWithContext(ctx, "GET", healthURL, nil)
+  if err != nil {
+    return nil, err
+  }
+  res, err := sc.httpClient.HTTPClient.Do(req)
+  if err != nil {
+    return nil, err
+  }
+  defer res.Body.Close()
+  if res.StatusCode != http.StatusOK {
+    return nil, fmt.Errorf("%s answered with status %d", healthURL, res.StatusCode)
+  }
+  var ready struct {
+    Details struct {
+      Classes []string `json:"classes"`
+    } `json:"details"`
+  }
+  if err := json.NewDecoder(res.Body).Decode(&ready); err != nil {
+    return nil, nil
+  }
+  return ready.Details.Classes, nil
+}
+
+func checkShards(shards []Shard) []*ShardHealth {
+  health := make([]*ShardHealth, len(shards))
+  var wg sync.WaitGroup
+  for i, shard := range shards {
+    i, shard := i, shard // https://golang.org/doc/faq#closures_and_goroutines
+    wg.Add(1)
+    go func() {
+      defer wg.Done()
+      shardHealth := &ShardHealth{
+        Name:      shard.Name(),
+        Healthy:   true,
+        CheckedAt: time.Now().UTC().Format(time.RFC3339),
+      }
+      classes, err := shard.CheckHealth()
+      if err != nil {
+        shardHealth.Healthy = false
+        shardHealth.Error = err.Error()
+      }
+      shardHealth.Classes = classes
+      health[i] = shardHealth
+    }()
+  }
+  wg.Wait()
+  return health
+}
+
+func sameShards(a, b []Shard) bool {
+  if len(a) != len(b) {
+    return false
+  }
+  for i := range a {
+    if a[i] != b[i] {
+      return false
+    }
+  }
+  return true
+}
+
+// CheckShards refreshes the health of the shards, the results are dropped if the shards were reloaded meanwhile.
+func (h *Handler) CheckShards() {
+  shards := h.getShards()
+  health := checkShards(shards)
+  h.mutex.Lock()
+  defer h.mutex.Unlock()
+  if sameShards(h.Shards, shards) {
+    h.setShardsHealth(health)
+  }
+}
+
+// setShardsHealth keeps the health of the shards along with the classes of their indexes. Expects the mutex locked.
+func (h *Handler) setShardsHealth(health []*ShardHealth) {
+  h.shardsHealth = health
+  for _, shardHealth := range health {
+    for _, class := range shardHealth.Classes {
+      h.classes.Store(class, true)
+    }
+  }
+}
+
+func (h *Handler) RunHealthChecks(interval time.Duration) {
+  for range time.Tick(interval) {
+    h.CheckShards()
+  }
+}
+
+// Health reports the merger ready when the share of the shards healthy at the last check is at least MinHealthyShards.
+func (h *Handler) Health() (bool, interface{}) {
+  h.mutex.RLock()
+  defer h.mutex.RUnlock()
+  health := &MergerHealth{
+    ShardsCount:      len(h.Shards),
+    MinHealthyShards: h.MinHealthyShards,
+    Shards:           h.shardsHealth,
+  }
+  for _, shardHealth := range h.shardsHealth {
+    if shardHealth.Healthy {
+      health.HealthyShardsCount++
+    }
+  }
+  if health.ShardsCount == 0 {
+    return false, health
+  }
+  health.HealthyShards = float64(health.HealthyShardsCount) / float64(health.ShardsCount)
+  return health.HealthyShardsCount > 0 && health.HealthyShards >= h.MinHealthyShards, health
+}
@@ -0,0 +1,58 @@
+package suggest_merger
+
+import (
+  "main/metrics"
+  "main/suggest"
+  "net/url"
+  "time"
+)
+
+var (
+  queriesCount = metrics.Default.NewCounterVec(
+    "suggest_merger_queries_total", "Number of merged suggest queries.", "class")
+  emptyQueriesCount = metrics.Default.NewCounterVec(
+    "suggest_merger_empty_queries_total", "Number of merged suggest queries with no suggestions found.", "class")
+  queryDuration = metrics.Default.NewHistogramVec(
+    "suggest_merger_query_duration_seconds", "Merged suggest query latency.", metrics.DefaultLatencyBuckets, "class")
+  badRequestsCount = metrics.Default.NewCounterVec(
+    "suggest_merger_bad_requests_total", "Number of rejected merger requests.")
+
+  shardRequestsCount = metrics.Default.NewCounterVec(
+    "suggest_merger_shard_requests_total", "Number of requests to the shard.", "shard")
+  shardErrorsCount = metrics.Default.NewCounterVec(
+    "suggest_merger_shard_errors_total", "Number of failed requests to the shard, after the retries.", "shard")
+  shardRetriesCount = metrics.Default.NewCounterVec(
+    "suggest_merger_shard_retries_total", "Number of retried requests to the shard.", "shard")
+  shardRequestDuration = metrics.Default.NewHistogramVec(
+    "suggest_merger_shard_request_duration_seconds", "Shard request latency, retries included.", metrics.DefaultLatencyBuckets, "shard")
+)
+
+// recordClasses remembers the classes of the suggestions the shards return, for the shards which do not report
+// the classes of their indexes on the health checks. The classes of the shard indexes bound the ones it sees.
+func (h *Handler) recordClasses(suggestions []*suggest.SuggestAnswerItem) {
+  for _, item := range suggestions {
+    h.classes.Store(item.Class(), true)
+  }
+}
+
+func (h *Handler) hasClass(class string) bool {
+  _, ok := h.classes.Load(class)
+  return ok
+}
+
+func (h *Handler) observeQuery(query url.Values, resultsCount int, start time.Time) {
+  class := suggest.ClassLabel(query["class"], query["exclude-class"], h.hasClass)
+  queriesCount.With(class).Inc()
+  if resultsCount == 0 {
+    emptyQueriesCount.With(class).Inc()
+  }
+  queryDuration.With(class).Observe(time.Since(start).Seconds())
+}
+
+func observeShardRequest(shard string, err error, start time.Time) {
+  shardRequestsCount.With(shard).Inc()
+  if err != nil {
+    shardErrorsCount.With(shard).Inc()
+  }
+  shardRequestDuration.With(shard).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,134 @@
+package suggest_merger
+
+import (
+  "encoding/json"
+  "fmt"
+  "google.golang.org/protobuf/proto"
+  stpb "main/proto/suggest/suggest_trie"
+  "main/suggest"
+  "main/tools"
+  "net/http"
+  "net/url"
+  "os"
+  "path/filepath"
+)
+
+type Shard interface {
+  GetSuggest(query url.Values, headers http.Header) (*suggest.PaginatedSuggestResponse, uint64, error)
+  // Name identifies the shard in the metrics.
+  Name() string
+  // CheckHealth checks whether the shard can take requests and returns the classes of its index when known.
+  CheckHealth() ([]string, error)
+}
+
+type RemoteShard struct {
+  Url           url.URL
+  SuggestClient *SuggestClient
+}
+
+func (rs *RemoteShard) Name() string {
+  return rs.Url.Host
+}
+
+// CheckHealth checks the readiness of the daemon serving the shard.
+func (rs *RemoteShard) CheckHealth() ([]string, error) {
+  healthUrl := url.URL{Scheme: rs.Url.Scheme, Host: rs.Url.Host, Path: "/health/ready"}
+  return rs.SuggestClient.CheckHealth(healthUrl.String())
+}
+
+func (rs *RemoteShard) GetSuggest(query url.Values, headers http.Header) (*suggest.PaginatedSuggestResponse, uint64, error) {
+  shardUrl := rs.Url
+  shardUrl.RawQuery = query.Encode()
+
+  requestHeaders := headers.Clone()
+  if requestHeaders == nil {
+    requestHeaders = http.Header{}
+  }
+  requestHeaders.Set("Accept", suggest.ProtoContentType+", application/json;q=0.9")
+
+  _, result, responseHeaders, err := rs.SuggestClient.Get(shardUrl.String(), requestHeaders)
+  if err != nil {
+    return nil, 0, err
+  }
+
+  if suggest.IsProtoContent(responseHeaders) {
+    response := &stpb.PaginatedSuggestResponse{}
+    if err := proto.Unmarshal(result, response); err != nil {
+      return nil, 0, err
+    }
+    return suggest.NewPaginatedSuggestResponseFromProto(response), getSuggestVersion(responseHeaders), nil
+  }
+
+  paginatedResponse := &suggest.PaginatedSuggestResponse{}
+  if err := json.Unmarshal(result, paginatedResponse); err != nil {
+    return nil, 0, err
+  }
+  return paginatedResponse, getSuggestVersion(responseHeaders), nil
+}
+
+type LocalShard struct {
+  Handler *suggest.Handler
+}
+
+func (ls *LocalShard) Name() string {
+  return ls.Handler.Name
+}
+
+func (ls *LocalShard) CheckHealth() ([]string, error) {
+  if ready, _ := ls.Handler.Health(); !ready {
+    return nil, fmt.Errorf("shard %s is not loaded", ls.Name())
+  }
+  return ls.Handler.Classes(), nil
+}
+
+func (ls *LocalShard) GetSuggest(query url.Values, _ http.Header) (*suggest.PaginatedSuggestResponse, uint64, error) {
+  response, err := ls.Handler.GetPaginatedSuggest(query)
+  if err != nil {
+    return nil, 0, err
+  }
+  return response, ls.Handler.Suggest.Version, nil
+}
+
+func newLocalShard(name string, suggestData *stpb.SuggestData, equalShapedNormalize bool) Shard {
+  h := &suggest.Handler{
+    Name:                 name,
+    Suggest:              suggestData,
+    Policy:               tools.GetPolicy(),
+    EqualShapedNormalize: equalShapedNormalize,
+  }
+  h.PrepareIndex()
+  return &LocalShard{Handler: h}
+}
+
+// LoadLocalShards loads the suggest_N.* files found next to the suggest data path.
+func LoadLocalShards(suggestDataPath string, equalShapedNormalize bool) ([]Shard, error) {
+  var shards []Shard
+  for shardNumber := 0; ; shardNumber++ {
+    shardDataPath := suggest.ShardDataPath(suggestDataPath, shardNumber)
+    if _, err := os.Stat(shardDataPath); os.IsNotExist(err) {
+      break
+    }
+    suggestData, err := suggest.LoadSuggest(shardDataPath)
+    if err != nil {
+      return nil, fmt.Errorf("cannot load shard %s: %v", shardDataPath, err)
+    }
+    shards = append(shards, newLocalShard(filepath.Base(shardDataPath), suggestData, equalShapedNormalize))
+  }
+  if len(shards) == 0 {
+    return nil, fmt.Errorf("no shards found for %s", suggestDataPath)
+  }
+  return shards, nil
+}
+
+// LoadManifestShards loads every file listed in the build manifest as a local shard.
+func LoadManifestShards(manifest *suggest.Manifest, equalShapedNormalize bool) ([]Shard, error) {
+  var shards []Shard
+  for _, manifestFile := range manifest.Files {
+    suggestData, err := manifest.LoadFile(manifestFile)
+    if err != nil {
+      return nil, fmt.Errorf("cannot load shard %s: %v", manifestFile.Path, err)
+    }
+    shards = append(shards, newLocalShard(filepath.Base(manifestFile.Path), suggestData, equalShapedNormalize))
+  }
+  return shards, nil
+}
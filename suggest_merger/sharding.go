@@ -0,0 +1,241 @@
+package suggest_merger
+
+import (
+  "fmt"
+  "hash/fnv"
+  "main/suggest"
+  "sort"
+  "strconv"
+  "unicode/utf8"
+)
+
+const (
+  FirstCharsSharding = "first-chars"
+  HashSharding       = "hash"
+  FieldSharding      = "field"
+)
+
+type ShardingOptions struct {
+  Strategy         string
+  PrefixLength     int
+  MaxPrefixLength  int
+  Field            string
+  CountOutputFiles int
+}
+
+// ShardingStrategy assigns items to shards in two steps: Key gives the finest routing key of an item and Plan
+// distributes the keys over the shards using the number of items per key.
+type ShardingStrategy interface {
+  Name() string
+  Key(item *suggest.Item) string
+  Plan(keysCount map[string]int, countParts int) *ShardingPlan
+}
+
+type ShardingPlan struct {
+  Strategy   string
+  Parameters map[string]string
+  ShardsKeys [][]string
+  keysShards map[string]int
+}
+
+func newShardingPlan(strategy ShardingStrategy, countParts int) *ShardingPlan {
+  plan := &ShardingPlan{
+    Strategy:   strategy.Name(),
+    ShardsKeys: make([][]string, countParts),
+    keysShards: map[string]int{},
+  }
+  for shardNumber := range plan.ShardsKeys {
+    plan.ShardsKeys[shardNumber] = []string{}
+  }
+  return plan
+}
+
+func (sp *ShardingPlan) assign(routingKey string, itemKeys []string, shardNumber int) {
+  sp.ShardsKeys[shardNumber] = append(sp.ShardsKeys[shardNumber], routingKey)
+  for _, key := range itemKeys {
+    sp.keysShards[key] = shardNumber
+  }
+}
+
+func (sp *ShardingPlan) ShardOf(key string) int {
+  return sp.keysShards[key]
+}
+
+func NewShardingStrategy(options *ShardingOptions) (ShardingStrategy, error) {
+  switch options.Strategy {
+  case FirstCharsSharding, "":
+    if options.PrefixLength < 1 {
+      return nil, fmt.Errorf("sharding prefix length must be positive, got %d", options.PrefixLength)
+    }
+    maxPrefixLength := options.MaxPrefixLength
+    if maxPrefixLength < options.PrefixLength {
+      maxPrefixLength = options.PrefixLength
+    }
+    return &FirstCharsStrategy{PrefixLength: options.PrefixLength, MaxPrefixLength: maxPrefixLength}, nil
+  case HashSharding:
+    if options.CountOutputFiles < 1 {
+      return nil, fmt.Errorf("hash sharding needs a positive number of output files, got %d", options.CountOutputFiles)
+    }
+    return &HashStrategy{CountParts: options.CountOutputFiles}, nil
+  case FieldSharding:
+    if options.Field == "" {
+      return nil, fmt.Errorf("please specify the data field to shard by via the --sharding-field parameter")
+    }
+    return &FieldStrategy{Field: options.Field}, nil
+  }
+  return nil, fmt.Errorf("unknown sharding strategy %q", options.Strategy)
+}
+
+// FirstCharsStrategy routes items by the first PrefixLength characters of their normalized text. Prefixes holding more
+// than a fair share of items are recursively split by the next character, up to MaxPrefixLength characters.
+type FirstCharsStrategy struct {
+  PrefixLength    int
+  MaxPrefixLength int
+}
+
+func (s *FirstCharsStrategy) Name() string {
+  return FirstCharsSharding
+}
+
+// runePrefix cuts the text to its first length characters, not bytes, so that a multibyte character is never split.
+func runePrefix(text string, length int) string {
+  for i := range text {
+    if length == 0 {
+      return text[:i]
+    }
+    length--
+  }
+  return text
+}
+
+func (s *FirstCharsStrategy) Key(item *suggest.Item) string {
+  return runePrefix(item.NormalizedText, s.MaxPrefixLength)
+}
+
+type prefixGroup struct {
+  Prefix string
+  Keys   []string
+  Count  int
+}
+
+func groupByPrefix(keys []string, keysCount map[string]int, prefixLength int) []*prefixGroup {
+  groupsMap := map[string]*prefixGroup{}
+  var groups []*prefixGroup
+  for _, key := range keys {
+    prefix := runePrefix(key, prefixLength)
+    group, ok := groupsMap[prefix]
+    if !ok {
+      group = &prefixGroup{Prefix: prefix}
+      groupsMap[prefix] = group
+      groups = append(groups, group)
+    }
+    group.Keys = append(group.Keys, key)
+    group.Count += keysCount[key]
+  }
+  return groups
+}
+
+func (s *FirstCharsStrategy) splitHotGroups(groups []*prefixGroup, keysCount map[string]int, maxCount int) []*prefixGroup {
+  var result []*prefixGroup
+  for _, group := range groups {
+    prefixLength := utf8.RuneCountInString(group.Prefix)
+    if group.Count <= maxCount || prefixLength >= s.MaxPrefixLength {
+      result = append(result, group)
+      continue
+    }
+    subGroups := groupByPrefix(group.Keys, keysCount, prefixLength+1)
+    if len(subGroups) == 1 && subGroups[0].Prefix == group.Prefix {
+      result = append(result, group)
+      continue
+    }
+    result = append(result, s.splitHotGroups(subGroups, keysCount, maxCount)...)
+  }
+  return result
+}
+
+func (s *FirstCharsStrategy) Plan(keysCount map[string]int, countParts int) *ShardingPlan {
+  keys := make([]string, 0, len(keysCount))
+  totalCount := 0
+  for key, count := range keysCount {
+    keys = append(keys, key)
+    totalCount += count
+  }
+  sort.Strings(keys)
+
+  groups := groupByPrefix(keys, keysCount, s.PrefixLength)
+  groups = s.splitHotGroups(groups, keysCount, totalCount/countParts)
+
+  groupsCount := map[string]int{}
+  groupsKeys := map[string][]string{}
+  for _, group := range groups {
+    groupsCount[group.Prefix] = group.Count
+    groupsKeys[group.Prefix] = group.Keys
+  }
+
+  plan := newShardingPlan(s, countParts)
+  plan.Parameters = map[string]string{
+    "prefix_length":     strconv.Itoa(s.PrefixLength),
+    "max_prefix_length": strconv.Itoa(s.MaxPrefixLength),
+  }
+  for shardNumber, prefixes := range getDistributionByParts(groupsCount, countParts) {
+    for _, prefix := range prefixes {
+      plan.assign(prefix, groupsKeys[prefix], shardNumber)
+    }
+  }
+  return plan
+}
+
+// HashStrategy spreads items uniformly by the hash of their id, so every shard holds a part of every prefix and
+// the merger has to fan out to all of them.
+type HashStrategy struct {
+  CountParts int
+}
+
+func (s *HashStrategy) Name() string {
+  return HashSharding
+}
+
+func (s *HashStrategy) Key(item *suggest.Item) string {
+  h := fnv.New32a()
+  h.Write([]byte(item.Id()))
+  return strconv.Itoa(int(h.Sum32() % uint32(s.CountParts)))
+}
+
+func (s *HashStrategy) Plan(_ map[string]int, countParts int) *ShardingPlan {
+  plan := newShardingPlan(s, countParts)
+  for shardNumber := 0; shardNumber < countParts; shardNumber++ {
+    key := strconv.Itoa(shardNumber)
+    plan.assign(key, []string{key}, shardNumber)
+  }
+  return plan
+}
+
+// FieldStrategy keeps all items with the same value of a data field, e.g. a tenant, in the same shard.
+type FieldStrategy struct {
+  Field string
+}
+
+func (s *FieldStrategy) Name() string {
+  return FieldSharding
+}
+
+func (s *FieldStrategy) Key(item *suggest.Item) string {
+  value, ok := item.Data[s.Field]
+  if !ok || value == nil {
+    return ""
+  }
+  return suggest.DataValueString(value)
+}
+
+func (s *FieldStrategy) Plan(keysCount map[string]int, countParts int) *ShardingPlan {
+  plan := newShardingPlan(s, countParts)
+  plan.Parameters = map[string]string{
+    "field": s.Field,
+  }
+  for shardNumber, values := range getDistributionByParts(keysCount, countParts) {
+    for _, value := range values {
+      plan.assign(value, []string{value}, shardNumber)
+    }
+  }
+  return plan
+}
@@ -0,0 +1,52 @@
+package suggest_personalization
+
+import (
+  "fmt"
+  "main/network"
+  "main/suggest"
+  "net/http"
+  "time"
+)
+
+type HistoryHandler struct {
+  Store   HistoryStore
+  Handler *suggest.Handler
+}
+
+// HandleSelectRequest records that the user selected the item with the given text. The parameters may be passed
+// in the query or as a form of the POST request.
+func (hh *HistoryHandler) HandleSelectRequest(w http.ResponseWriter, r *http.Request) {
+  if !network.RequirePost(w, r) {
+    return
+  }
+  user := r.FormValue("user")
+  text := r.FormValue("text")
+  if user == "" || text == "" {
+    network.ReportBadRequest(w, "please specify the user and text parameters")
+    return
+  }
+  item := hh.Handler.GetItem(text)
+  if item == nil {
+    network.ReportBadRequest(w, fmt.Sprintf("unknown item %q", text))
+    return
+  }
+  hh.Store.Add(user, &Selection{
+    ItemId: suggest.ItemId(item),
+    Class:  suggest.ItemClass(item),
+    Time:   time.Now(),
+  })
+  network.ReportSuccessMessage(w, "OK")
+}
+
+func (hh *HistoryHandler) HandleHistoryRequest(w http.ResponseWriter, r *http.Request) {
+  user := r.URL.Query().Get("user")
+  if user == "" {
+    network.ReportBadRequest(w, "please specify the user parameter")
+    return
+  }
+  history := hh.Store.Get(user)
+  if history == nil {
+    history = &UserHistory{User: user}
+  }
+  network.ReportSuccessData(w, history)
+}
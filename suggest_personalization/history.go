@@ -0,0 +1,141 @@
+package suggest_personalization
+
+import (
+  "container/list"
+  "encoding/json"
+  "log"
+  "os"
+  "sync"
+  "time"
+)
+
+type Selection struct {
+  ItemId string    `json:"item_id"`
+  Class  string    `json:"class"`
+  Time   time.Time `json:"time"`
+}
+
+type UserHistory struct {
+  User       string       `json:"user"`
+  Selections []*Selection `json:"selections"`
+}
+
+// HistoryStore keeps the recent selections of every user.
+type HistoryStore interface {
+  Add(user string, selection *Selection)
+  Get(user string) *UserHistory
+}
+
+// LRUHistoryStore keeps the last MaxSelections selections of at most Capacity users, evicting the users
+// which were not seen for the longest time.
+type LRUHistoryStore struct {
+  Capacity      int
+  MaxSelections int
+
+  mutex    sync.Mutex
+  users    *list.List
+  elements map[string]*list.Element
+}
+
+func NewLRUHistoryStore(capacity, maxSelections int) *LRUHistoryStore {
+  return &LRUHistoryStore{
+    Capacity:      capacity,
+    MaxSelections: maxSelections,
+    users:         list.New(),
+    elements:      map[string]*list.Element{},
+  }
+}
+
+func (s *LRUHistoryStore) add(history *UserHistory) {
+  if len(history.Selections) > s.MaxSelections {
+    history.Selections = history.Selections[len(history.Selections)-s.MaxSelections:]
+  }
+  if element, ok := s.elements[history.User]; ok {
+    element.Value = history
+    s.users.MoveToFront(element)
+    return
+  }
+  s.elements[history.User] = s.users.PushFront(history)
+  for s.users.Len() > s.Capacity {
+    oldest := s.users.Back()
+    s.users.Remove(oldest)
+    delete(s.elements, oldest.Value.(*UserHistory).User)
+  }
+}
+
+func (s *LRUHistoryStore) Add(user string, selection *Selection) {
+  s.mutex.Lock()
+  defer s.mutex.Unlock()
+  history := &UserHistory{User: user}
+  if element, ok := s.elements[user]; ok {
+    history = element.Value.(*UserHistory)
+  }
+  history.Selections = append(history.Selections, selection)
+  s.add(history)
+}
+
+// Get returns a copy of the user history, nil for unknown users.
+func (s *LRUHistoryStore) Get(user string) *UserHistory {
+  s.mutex.Lock()
+  defer s.mutex.Unlock()
+  element, ok := s.elements[user]
+  if !ok {
+    return nil
+  }
+  s.users.MoveToFront(element)
+  history := element.Value.(*UserHistory)
+  return &UserHistory{
+    User:       history.User,
+    Selections: append([]*Selection{}, history.Selections...),
+  }
+}
+
+// Snapshot writes the histories from the least to the most recently seen user, so that loading the
+// snapshot restores the eviction order.
+func (s *LRUHistoryStore) Snapshot(path string) error {
+  s.mutex.Lock()
+  histories := make([]*UserHistory, 0, s.users.Len())
+  for element := s.users.Back(); element != nil; element = element.Prev() {
+    histories = append(histories, element.Value.(*UserHistory))
+  }
+  b, err := json.Marshal(histories)
+  s.mutex.Unlock()
+  if err != nil {
+    return err
+  }
+  tmpPath := path + ".tmp"
+  if err := os.WriteFile(tmpPath, b, 0644); err != nil {
+    return err
+  }
+  return os.Rename(tmpPath, path)
+}
+
+// LoadLRUHistoryStore restores the store from a snapshot, a missing snapshot gives an empty store.
+func LoadLRUHistoryStore(path string, capacity, maxSelections int) (*LRUHistoryStore, error) {
+  s := NewLRUHistoryStore(capacity, maxSelections)
+  b, err := os.ReadFile(path)
+  if os.IsNotExist(err) {
+    return s, nil
+  }
+  if err != nil {
+    return nil, err
+  }
+  var histories []*UserHistory
+  if err := json.Unmarshal(b, &histories); err != nil {
+    return nil, err
+  }
+  for _, history := range histories {
+    s.add(history)
+  }
+  return s, nil
+}
+
+func (s *LRUHistoryStore) RunSnapshots(path string, interval time.Duration) {
+  ticker := time.NewTicker(interval)
+  defer ticker.Stop()
+  for range ticker.C {
+    if err := s.Snapshot(path); err != nil {
+      log.Printf("cannot write the history snapshot: %v", err)
+    }
+  }
+}
@@ -0,0 +1,71 @@
+package suggest_personalization
+
+import (
+  "main/suggest"
+  "sort"
+)
+
+// Reranker blends the global item weights with the user preferences: the items the user selected before
+// and the classes of their recent selections. Factor 0 keeps the global order, factor 1 orders by the
+// user history alone. The blended scores replace the weights, so that the pages follow the reranked order.
+type Reranker struct {
+  Store  HistoryStore
+  Factor float64
+}
+
+func maxCount(counts map[string]int) int {
+  result := 0
+  for _, count := range counts {
+    if count > result {
+      result = count
+    }
+  }
+  return result
+}
+
+func (r *Reranker) Rerank(user string, suggestions []*suggest.SuggestAnswerItem) []*suggest.SuggestAnswerItem {
+  history := r.Store.Get(user)
+  if history == nil || len(history.Selections) == 0 || len(suggestions) < 2 {
+    return suggestions
+  }
+
+  itemsCounts := map[string]int{}
+  classesCounts := map[string]int{}
+  for _, selection := range history.Selections {
+    itemsCounts[selection.ItemId]++
+    if selection.Class != "" {
+      classesCounts[selection.Class]++
+    }
+  }
+  maxItemsCount := float64(maxCount(itemsCounts))
+  maxClassesCount := float64(maxCount(classesCounts))
+
+  var maxWeight float64
+  for _, item := range suggestions {
+    if float64(item.Weight) > maxWeight {
+      maxWeight = float64(item.Weight)
+    }
+  }
+
+  scores := make(map[*suggest.SuggestAnswerItem]float64, len(suggestions))
+  for _, item := range suggestions {
+    var global, personal float64
+    if maxWeight > 0 {
+      global = float64(item.Weight) / maxWeight
+    }
+    personal = 2 * float64(itemsCounts[item.Id()]) / maxItemsCount
+    if maxClassesCount > 0 {
+      personal += float64(classesCounts[item.Class()]) / maxClassesCount
+    }
+    scores[item] = (1-r.Factor)*global + r.Factor*personal/3
+  }
+  for item, score := range scores {
+    item.Weight = float32(score)
+  }
+
+  reranked := append([]*suggest.SuggestAnswerItem{}, suggestions...)
+  sort.SliceStable(reranked, func(i, j int) bool {
+    return reranked[i].Weight > reranked[j].Weight
+  })
+  return reranked
+}
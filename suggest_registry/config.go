@@ -0,0 +1,92 @@
+package suggest_registry
+
+import (
+  "encoding/json"
+  "fmt"
+  "io/ioutil"
+  "main/suggest"
+  "os"
+  "path/filepath"
+  "regexp"
+  "strings"
+)
+
+type IndexConfig struct {
+  Suggest       string `json:"suggest"`
+  Manifest      string `json:"manifest"`
+  Normalization string `json:"normalization"`
+  DefaultCount  int    `json:"default_count"`
+}
+
+type Config struct {
+  Indexes map[string]*IndexConfig `json:"indexes"`
+}
+
+var indexNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// batchIndexName is reserved, /suggest/batch is the batch endpoint taking the index from the parameter.
+const batchIndexName = "batch"
+
+func validIndexName(name string) bool {
+  return indexNameRegexp.MatchString(name) && name != batchIndexName
+}
+
+func ReadConfig(configPath string) (*Config, error) {
+  b, err := os.ReadFile(configPath)
+  if err != nil {
+    return nil, err
+  }
+  config := &Config{}
+  if err := json.Unmarshal(b, config); err != nil {
+    return nil, err
+  }
+  configDir := filepath.Dir(configPath)
+  for name, indexConfig := range config.Indexes {
+    if !validIndexName(name) {
+      return nil, fmt.Errorf("invalid index name %q", name)
+    }
+    if (indexConfig.Suggest == "") == (indexConfig.Manifest == "") {
+      return nil, fmt.Errorf("index %q: exactly one of suggest and manifest should be set", name)
+    }
+    switch indexConfig.Normalization {
+    case "", suggest.DefaultNormalization, suggest.EqualShapedNormalization:
+    default:
+      return nil, fmt.Errorf("index %q: unknown normalization %q", name, indexConfig.Normalization)
+    }
+    for _, path := range []*string{&indexConfig.Suggest, &indexConfig.Manifest} {
+      if *path != "" && !filepath.IsAbs(*path) {
+        *path = filepath.Join(configDir, *path)
+      }
+    }
+  }
+  return config, nil
+}
+
+// ScanDir makes a config of the indexes found in the directory: every build output directory with
+// a manifest is an index named after the directory, every *.data file is one named after the file.
+func ScanDir(dir string) (*Config, error) {
+  entries, err := ioutil.ReadDir(dir)
+  if err != nil {
+    return nil, err
+  }
+  config := &Config{Indexes: map[string]*IndexConfig{}}
+  for _, entry := range entries {
+    path := filepath.Join(dir, entry.Name())
+    if strings.HasPrefix(entry.Name(), ".") {
+      continue
+    }
+    if entry.IsDir() {
+      if !validIndexName(entry.Name()) {
+        continue
+      }
+      if _, err := os.Stat(filepath.Join(path, suggest.ManifestFileName)); err == nil {
+        config.Indexes[entry.Name()] = &IndexConfig{Manifest: path}
+      }
+      continue
+    }
+    if name := strings.TrimSuffix(entry.Name(), ".data"); name != entry.Name() && validIndexName(name) {
+      config.Indexes[name] = &IndexConfig{Suggest: path}
+    }
+  }
+  return config, nil
+}
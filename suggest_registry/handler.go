@@ -0,0 +1,126 @@
+package suggest_registry
+
+import (
+  "fmt"
+  "main/network"
+  "main/suggest"
+  "net/http"
+  "strings"
+)
+
+type IndexInfo struct {
+  Name                 string `json:"name"`
+  Version              uint64 `json:"version"`
+  ItemsCount           int    `json:"items_count"`
+  EqualShapedNormalize bool   `json:"equal_shaped_normalize"`
+  DefaultCount         int    `json:"default_count"`
+}
+
+// indexName takes the index name from the /suggest/{index} path or from the index parameter, and tells
+// whether the request is a batch one, sent to /suggest/{index}/batch or /suggest/batch.
+func indexName(r *http.Request) (string, bool) {
+  name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/suggest"), "/")
+  batch := false
+  if name == batchIndexName || strings.HasSuffix(name, "/"+batchIndexName) {
+    name = strings.TrimSuffix(strings.TrimSuffix(name, batchIndexName), "/")
+    batch = true
+  }
+  if name == "" {
+    name = r.URL.Query().Get("index")
+  }
+  return name, batch
+}
+
+func reportNotFound(w http.ResponseWriter, message string) {
+  network.WriteCORSHeaders(w)
+  http.Error(w, message, http.StatusNotFound)
+}
+
+func (r *Registry) HandleSuggestRequest(w http.ResponseWriter, req *http.Request) {
+  name, batch := indexName(req)
+  if name == "" {
+    network.ReportBadRequest(w, "please specify the index via the /suggest/{index} path or the index parameter")
+    return
+  }
+  h := r.Get(name)
+  if h == nil {
+    reportNotFound(w, fmt.Sprintf("unknown index %q", name))
+    return
+  }
+  if batch {
+    h.HandleBatchSuggestRequest(w, req)
+    return
+  }
+  h.HandleSuggestRequest(w, req)
+}
+
+func (r *Registry) HandleIndexesRequest(w http.ResponseWriter, _ *http.Request) {
+  infos := make([]*IndexInfo, 0)
+  for _, name := range r.Names() {
+    h := r.Get(name)
+    if h == nil {
+      continue
+    }
+    infos = append(infos, &IndexInfo{
+      Name:                 name,
+      Version:              h.Suggest.Version,
+      ItemsCount:           len(h.Suggest.Items),
+      EqualShapedNormalize: h.EqualShapedNormalize,
+      DefaultCount:         h.DefaultCount,
+    })
+  }
+  network.ReportSuccessData(w, infos)
+}
+
+func (r *Registry) HandleLoadRequest(w http.ResponseWriter, req *http.Request) {
+  if !network.RequirePost(w, req) {
+    return
+  }
+  name := req.URL.Query().Get("index")
+  if name == "" {
+    network.ReportBadRequest(w, "please specify the index parameter")
+    return
+  }
+  if err := r.Load(name); err != nil {
+    network.ReportServerError(w, fmt.Sprintf("%v", err))
+    return
+  }
+  network.ReportSuccessMessage(w, "OK")
+}
+
+func (r *Registry) HandleUnloadRequest(w http.ResponseWriter, req *http.Request) {
+  if !network.RequirePost(w, req) {
+    return
+  }
+  name := req.URL.Query().Get("index")
+  if !r.Unload(name) {
+    reportNotFound(w, fmt.Sprintf("index %q is not loaded", name))
+    return
+  }
+  network.ReportSuccessMessage(w, "OK")
+}
+
+func (r *Registry) HandleHealthRequest(w http.ResponseWriter, _ *http.Request) {
+  ready, _ := r.Health()
+  network.ReportHealth(w, ready)
+}
+
+type RegistryHealth struct {
+  Indexes []*suggest.IndexHealth `json:"indexes"`
+}
+
+// Health reports the registry ready when it serves at least one index and every served index is loaded.
+func (r *Registry) Health() (bool, interface{}) {
+  health := &RegistryHealth{Indexes: []*suggest.IndexHealth{}}
+  ready := true
+  for _, name := range r.Names() {
+    h := r.Get(name)
+    if h == nil {
+      continue
+    }
+    indexHealth := h.IndexHealth()
+    health.Indexes = append(health.Indexes, indexHealth)
+    ready = ready && indexHealth.Loaded
+  }
+  return ready && len(health.Indexes) > 0, health
+}
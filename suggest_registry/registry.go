@@ -0,0 +1,149 @@
+package suggest_registry
+
+import (
+  "fmt"
+  "log"
+  "main/access_log"
+  stpb "main/proto/suggest/suggest_trie"
+  "main/suggest"
+  "main/tools"
+  "sort"
+  "sync"
+)
+
+// Registry serves a set of named indexes, each of them loaded and unloaded independently. The indexes
+// are described either by a config file or by the contents of a directory, which are re-read on every load
+// so that new indexes can be added without a restart.
+type Registry struct {
+  ConfigPath   string
+  Dir          string
+  MaxBatchSize int
+  AccessLog    *access_log.Logger
+
+  mutex    sync.RWMutex
+  handlers map[string]*suggest.Handler
+}
+
+func NewRegistry(configPath, dir string) (*Registry, error) {
+  if (configPath == "") == (dir == "") {
+    return nil, fmt.Errorf("please specify either the indexes config or the indexes directory")
+  }
+  return &Registry{
+    ConfigPath: configPath,
+    Dir:        dir,
+    handlers:   map[string]*suggest.Handler{},
+  }, nil
+}
+
+func (r *Registry) readConfig() (*Config, error) {
+  if r.ConfigPath != "" {
+    return ReadConfig(r.ConfigPath)
+  }
+  return ScanDir(r.Dir)
+}
+
+func (r *Registry) newHandler(name string, indexConfig *IndexConfig) (*suggest.Handler, error) {
+  var suggestData *stpb.SuggestData
+  normalization := indexConfig.Normalization
+  if indexConfig.Manifest != "" {
+    manifest, err := suggest.ReadManifest(indexConfig.Manifest)
+    if err != nil {
+      return nil, err
+    }
+    if manifest.Sharding != nil || len(manifest.Files) != 1 {
+      return nil, fmt.Errorf("%s describes a sharded build", indexConfig.Manifest)
+    }
+    if suggestData, err = manifest.LoadFile(manifest.Files[0]); err != nil {
+      return nil, err
+    }
+    if normalization == "" {
+      normalization = manifest.Parameters.Normalization
+    }
+  } else {
+    var err error
+    if suggestData, err = suggest.LoadSuggest(indexConfig.Suggest); err != nil {
+      return nil, err
+    }
+  }
+  h := &suggest.Handler{
+    Name:                 name,
+    Suggest:              suggestData,
+    Policy:               tools.GetPolicy(),
+    EqualShapedNormalize: normalization == suggest.EqualShapedNormalization,
+    DefaultCount:         indexConfig.DefaultCount,
+    MaxBatchSize:         r.MaxBatchSize,
+    AccessLog:            r.AccessLog,
+  }
+  h.PrepareIndex()
+  return h, nil
+}
+
+// Load loads the named index or replaces the loaded one, the previous version keeps serving on errors.
+func (r *Registry) Load(name string) error {
+  config, err := r.readConfig()
+  if err != nil {
+    return err
+  }
+  indexConfig, ok := config.Indexes[name]
+  if !ok {
+    return fmt.Errorf("unknown index %q", name)
+  }
+  h, err := r.newHandler(name, indexConfig)
+  if err != nil {
+    return fmt.Errorf("cannot load index %q: %v", name, err)
+  }
+  r.mutex.Lock()
+  r.handlers[name] = h
+  r.mutex.Unlock()
+  h.SetIndexMetrics()
+  log.Printf("loaded index %q, version %d, %d items", name, h.Suggest.Version, len(h.Suggest.Items))
+  return nil
+}
+
+func (r *Registry) LoadAll() error {
+  config, err := r.readConfig()
+  if err != nil {
+    return err
+  }
+  names := make([]string, 0, len(config.Indexes))
+  for name := range config.Indexes {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+  for _, name := range names {
+    if err := r.Load(name); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+// Unload stops serving the index and returns whether it was loaded.
+func (r *Registry) Unload(name string) bool {
+  r.mutex.Lock()
+  defer r.mutex.Unlock()
+  if _, ok := r.handlers[name]; !ok {
+    return false
+  }
+  delete(r.handlers, name)
+  suggest.DeleteIndexMetrics(name)
+  log.Printf("unloaded index %q", name)
+  return true
+}
+
+func (r *Registry) Get(name string) *suggest.Handler {
+  r.mutex.RLock()
+  defer r.mutex.RUnlock()
+  return r.handlers[name]
+}
+
+func (r *Registry) Names() []string {
+  r.mutex.RLock()
+  defer r.mutex.RUnlock()
+  names := make([]string, 0, len(r.handlers))
+  for name := range r.handlers {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+  return names
+}
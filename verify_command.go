@@ -0,0 +1,62 @@
+package main
+
+import (
+  "fmt"
+  "log"
+  "main/suggest"
+  "os"
+  "strings"
+)
+
+func NewVerifyCommand() *Command {
+  c := NewCommand("verify", "check suggest data files, build manifests or build output directories and report whether they can be served")
+  c.ArgsUsage = "<path>..."
+
+  c.Run = func(paths []string) error {
+    if len(paths) == 0 {
+      return fmt.Errorf("please specify the paths to verify")
+    }
+    ok := true
+    for _, path := range paths {
+      ok = RunVerify(path) && ok
+    }
+    if !ok {
+      os.Exit(1)
+    }
+    return nil
+  }
+  return c
+}
+
+// RunVerify checks a suggest data file, or every file of a build manifest, and reports whether they can be served.
+func RunVerify(path string) bool {
+  report := func(name string, r *suggest.VerifyReport, err error) bool {
+    if err != nil {
+      log.Printf("%s: FAILED: %v", name, err)
+      return false
+    }
+    log.Printf("%s: OK, format version %d, version %d, %d items, %d nodes", name, r.FormatVersion, r.Version, r.ItemsCount, r.NodesCount)
+    return true
+  }
+
+  if info, err := os.Stat(path); err == nil && (info.IsDir() || strings.HasSuffix(path, ".json")) {
+    manifest, err := suggest.ReadManifest(path)
+    if err != nil {
+      log.Printf("%s: FAILED: %v", path, err)
+      return false
+    }
+    ok := true
+    for _, manifestFile := range manifest.Files {
+      r, err := manifest.VerifyFile(manifestFile)
+      ok = report(manifest.FilePath(manifestFile), r, err) && ok
+    }
+    return ok
+  }
+
+  b, err := os.ReadFile(path)
+  if err != nil {
+    return report(path, nil, err)
+  }
+  r, err := suggest.VerifySuggest(b)
+  return report(path, r, err)
+}